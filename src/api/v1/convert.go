@@ -0,0 +1,222 @@
+package v1
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// NewTask converts an internal task into its API representation.
+//
+// Parameters:
+//   - t: The task
+//   - worker: The address of the worker the task is assigned to; empty if none
+func NewTask(t *task.Task, worker string) Task {
+	status := TaskStatus{
+		State:       t.State.String(),
+		Worker:      worker,
+		ContainerID: t.ContainerID,
+		StartTime:   timePtr(t.StartTime),
+		EndTime:     timePtr(t.EndTime),
+		ExitCode:    t.ExitCode,
+		Reason:      t.Reason,
+		Attempts:    t.Attempts,
+		Health:      string(t.Health),
+		StopOutcome: string(t.StopOutcome),
+		Outputs:     t.Outputs,
+	}
+	if u := t.Usage; u != nil {
+		status.Usage = &Usage{
+			Time:            u.Time,
+			CPUPercent:      u.CPUPercent,
+			MemoryUsage:     u.MemoryUsage,
+			MemoryPercent:   u.MemoryPercent,
+			NetworkRxBytes:  u.NetworkRxBytes,
+			NetworkTxBytes:  u.NetworkTxBytes,
+			BlockReadBytes:  u.BlockReadBytes,
+			BlockWriteBytes: u.BlockWriteBytes,
+		}
+	}
+	return Task{ID: t.ID.String(), Spec: NewTaskSpec(t), Status: status}
+}
+
+// NewTaskSpec returns the desired state of an internal task.
+func NewTaskSpec(t *task.Task) TaskSpec {
+	spec := TaskSpec{
+		Name:             t.Name,
+		Image:            t.Image,
+		Kind:             string(t.Kind),
+		Cpu:              t.Cpu,
+		Memory:           t.Memory,
+		Disk:             t.Disk,
+		Env:              t.Env,
+		PortBindings:     t.PortBindings,
+		RestartPolicy:    t.RestartPolicy,
+		PullPolicy:       string(t.PullPolicy),
+		ImagePullSecret:  t.ImagePullSecret,
+		StopSignal:       t.StopSignal,
+		StopGracePeriod:  durationString(t.StopGracePeriod),
+		PreStop:          newHook(t.PreStop),
+		HealthCheck:      newHook(t.HealthCheck),
+		ActiveDeadline:   durationString(t.ActiveDeadline),
+		BackoffLimit:     t.BackoffLimit,
+		TTLAfterFinished: durationString(t.TTLAfterFinished),
+		OutputsFile:      t.OutputsFile,
+		Labels:           t.Labels,
+	}
+	for p := range t.ExposedPorts {
+		spec.Ports = append(spec.Ports, string(p))
+	}
+	slices.Sort(spec.Ports)
+	for _, a := range t.Artifacts {
+		spec.Artifacts = append(spec.Artifacts, Artifact{Name: a.Name, Path: a.Path})
+	}
+	return spec
+}
+
+// NewTaskEvent converts an internal task event into its API representation.
+func NewTaskEvent(e *task.Event) TaskEvent {
+	return TaskEvent{
+		ID:        e.ID.String(),
+		TaskID:    e.Task.ID.String(),
+		State:     e.State.String(),
+		Timestamp: e.Timestamp,
+	}
+}
+
+// Task converts the request into an internal task in the Pending state and
+// checks it with task.Task.Validate. The request is expected to have been
+// validated against the OpenAPI document; values that still cannot be
+// converted or are invalid are reported as a *ValidationError.
+func (r CreateTaskRequest) Task() (task.Task, error) {
+	t, err := r.Convert()
+	if err != nil {
+		return task.Task{}, err
+	}
+	if fields := t.Validate(); len(fields) > 0 {
+		return task.Task{}, &ValidationError{Fields: TaskFieldErrors(fields)}
+	}
+	return t, nil
+}
+
+// Convert converts the request into an internal task in the Pending state like
+// Task, without validating the task.
+func (r CreateTaskRequest) Convert() (task.Task, error) {
+	var errs ValidationError
+	t := task.Task{State: task.Pending}
+	if r.ID != "" {
+		id, err := uuid.Parse(r.ID)
+		if err != nil {
+			errs.add("id", "must be a UUID")
+		}
+		t.ID = id
+	}
+
+	s := r.Spec
+	t.Name = s.Name
+	t.Image = s.Image
+	t.Kind = task.Kind(s.Kind)
+	t.Cpu = s.Cpu
+	t.Memory = s.Memory
+	t.Disk = s.Disk
+	t.Env = s.Env
+	t.PortBindings = s.PortBindings
+	t.RestartPolicy = s.RestartPolicy
+	t.PullPolicy = task.PullPolicy(s.PullPolicy)
+	t.ImagePullSecret = s.ImagePullSecret
+	t.StopSignal = s.StopSignal
+	t.BackoffLimit = s.BackoffLimit
+	t.OutputsFile = s.OutputsFile
+	t.Labels = s.Labels
+	t.StopGracePeriod = errs.duration("spec.stopGracePeriod", s.StopGracePeriod)
+	t.ActiveDeadline = errs.duration("spec.activeDeadline", s.ActiveDeadline)
+	t.TTLAfterFinished = errs.duration("spec.ttlAfterFinished", s.TTLAfterFinished)
+	t.PreStop = errs.hook("spec.preStop", s.PreStop)
+	t.HealthCheck = errs.hook("spec.healthCheck", s.HealthCheck)
+
+	if len(s.Ports) > 0 {
+		t.ExposedPorts = nat.PortSet{}
+		for i, p := range s.Ports {
+			port, ok := errs.port(fieldIndex("spec.ports", i), p)
+			if ok {
+				t.ExposedPorts[port] = struct{}{}
+			}
+		}
+	}
+	for _, a := range s.Artifacts {
+		t.Artifacts = append(t.Artifacts, task.Artifact{Name: a.Name, Path: a.Path})
+	}
+
+	if len(errs.Fields) > 0 {
+		return task.Task{}, &errs
+	}
+	return t, nil
+}
+
+func newHook(h *task.Hook) *Hook {
+	if h == nil {
+		return nil
+	}
+	hook := &Hook{Exec: h.Exec}
+	if h.HTTP != nil {
+		hook.HTTP = &HTTPHook{Port: string(h.HTTP.Port), Path: h.HTTP.Path, Method: h.HTTP.Method}
+	}
+	return hook
+}
+
+// duration parses an optional duration, recording an error for field if it is invalid.
+func (e *ValidationError) duration(field, s string) time.Duration {
+	if s == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		e.add(field, "must be a duration such as 30s or 1h30m")
+	}
+	return d
+}
+
+// hook converts an optional hook, recording an error for field if its port is invalid.
+func (e *ValidationError) hook(field string, h *Hook) *task.Hook {
+	if h == nil {
+		return nil
+	}
+	hook := &task.Hook{Exec: h.Exec}
+	if h.HTTP != nil {
+		port, _ := e.port(field+".http.port", h.HTTP.Port)
+		hook.HTTP = &task.HTTPHook{Port: port, Path: h.HTTP.Path, Method: h.HTTP.Method}
+	}
+	return hook
+}
+
+// port parses a container port such as "80" or "53/udp", defaulting to TCP.
+func (e *ValidationError) port(field, s string) (nat.Port, bool) {
+	number, proto, ok := strings.Cut(s, "/")
+	if !ok {
+		proto = "tcp"
+	}
+	port, err := nat.NewPort(proto, number)
+	if err != nil {
+		e.add(field, "must be a port such as 80 or 53/udp")
+		return "", false
+	}
+	return port, true
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func durationString(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/utkarsh5026/Orchestra/handler"
+)
+
+// ValidationError is returned when a request does not conform to the API.
+type ValidationError struct {
+	Fields []handler.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	return "invalid request: " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) add(field, message string) {
+	e.Fields = append(e.Fields, handler.FieldError{Field: field, Message: message})
+}
+
+func fieldIndex(field string, i int) string {
+	return fmt.Sprintf("%s[%d]", field, i)
+}
+
+func fieldKey(field, key string) string {
+	if field == "" {
+		return key
+	}
+	return field + "." + key
+}
+
+// specFields maps the fields of task.Task to the JSON names of the fields of
+// TaskSpec they are converted from.
+var specFields = func() map[string]string {
+	fields := map[string]string{"ExposedPorts": "ports"}
+	t := reflect.TypeFor[TaskSpec]()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		fields[f.Name] = name
+	}
+	return fields
+}()
+
+// TaskFieldErrors renames the field errors reported for a task.Task, such as by
+// task.Task.Validate, after the fields of the CreateTaskRequest it was
+// converted from, e.g. "PreStop.HTTP.Port" to "spec.preStop.http.port".
+func TaskFieldErrors(fields []handler.FieldError) []handler.FieldError {
+	renamed := make([]handler.FieldError, len(fields))
+	for i, f := range fields {
+		renamed[i] = handler.FieldError{Field: requestField(f.Field), Message: f.Message}
+	}
+	return renamed
+}
+
+func requestField(field string) string {
+	if field == "ID" {
+		return "id"
+	}
+	end := strings.IndexAny(field, ".[")
+	if end < 0 {
+		end = len(field)
+	}
+	top, rest := field[:end], field[end:]
+	if top == "ExposedPorts" {
+		// The ports of the request are a list, not a set keyed by port.
+		return "spec.ports"
+	}
+	name, ok := specFields[top]
+	if !ok {
+		name = strings.ToLower(top[:1]) + top[1:]
+	}
+	if !strings.HasPrefix(rest, "[") {
+		rest = strings.ToLower(rest)
+	} else if i := strings.Index(rest, "]"); i >= 0 {
+		rest = rest[:i+1] + strings.ToLower(rest[i+1:])
+	}
+	return "spec." + name + rest
+}
@@ -0,0 +1,105 @@
+package v1
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+//go:embed openapi.json
+var document []byte
+
+// Document returns the OpenAPI 3 document describing the API, as JSON.
+func Document() []byte {
+	return document
+}
+
+// schema is a JSON schema object of the OpenAPI document.
+type schema = map[string]any
+
+// operation is what the validator needs to know about an operation of the document.
+type operation struct {
+	params       []parameter
+	body         schema
+	bodyRequired bool
+}
+
+// parameter is a path or query parameter of an operation.
+type parameter struct {
+	name     string
+	in       string
+	required bool
+	schema   schema
+}
+
+// openAPI is the parsed document.
+type openAPI struct {
+	root       map[string]any
+	operations map[string]operation
+}
+
+var api = mustLoad(document)
+
+// mustLoad parses the document and indexes its operations by operationId. The
+// document is embedded in the binary, so failing to parse it is a bug.
+func mustLoad(doc []byte) *openAPI {
+	a := &openAPI{operations: make(map[string]operation)}
+	if err := json.Unmarshal(doc, &a.root); err != nil {
+		panic(fmt.Sprintf("invalid OpenAPI document: %v", err))
+	}
+
+	paths, _ := a.root["paths"].(map[string]any)
+	for _, p := range paths {
+		item, _ := p.(map[string]any)
+		shared, _ := item["parameters"].([]any)
+		for method, o := range item {
+			if method == "parameters" {
+				continue
+			}
+			op, _ := o.(map[string]any)
+			id, _ := op["operationId"].(string)
+			own, _ := op["parameters"].([]any)
+
+			var parsed operation
+			for _, ref := range append(append([]any{}, shared...), own...) {
+				p := a.resolve(ref)
+				s, _ := p["schema"].(map[string]any)
+				required, _ := p["required"].(bool)
+				parsed.params = append(parsed.params, parameter{
+					name:     p["name"].(string),
+					in:       p["in"].(string),
+					required: required,
+					schema:   s,
+				})
+			}
+			if rb, ok := op["requestBody"].(map[string]any); ok {
+				parsed.bodyRequired, _ = rb["required"].(bool)
+				content, _ := rb["content"].(map[string]any)
+				media, _ := content["application/json"].(map[string]any)
+				parsed.body, _ = media["schema"].(map[string]any)
+			}
+			a.operations[id] = parsed
+		}
+	}
+	return a
+}
+
+// resolve follows the $ref of a document object, if it has one.
+func (a *openAPI) resolve(v any) map[string]any {
+	obj, _ := v.(map[string]any)
+	ref, ok := obj["$ref"].(string)
+	if !ok {
+		return obj
+	}
+
+	var node any = a.root
+	for _, key := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		m, _ := node.(map[string]any)
+		node = m[key]
+	}
+	if node == nil {
+		panic(fmt.Sprintf("invalid OpenAPI document: unresolved reference %s", ref))
+	}
+	return a.resolve(node)
+}
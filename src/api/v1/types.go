@@ -0,0 +1,188 @@
+// Package v1 defines version 1 of the manager API: the request and response
+// types exchanged with clients, their conversion from and to the internal
+// types, and the OpenAPI document that describes the API and validates its
+// requests.
+//
+// The types of this package only change in backwards compatible ways; the
+// internal types they are converted from are free to change.
+package v1
+
+import (
+	"time"
+
+	"github.com/utkarsh5026/Orchestra/handler"
+)
+
+// CreateTaskRequest is the body of POST /v1/tasks.
+type CreateTaskRequest struct {
+	// ID is the ID of the new task, a UUID; the manager assigns one if it is empty.
+	ID   string   `json:"id,omitempty"`
+	Spec TaskSpec `json:"spec"`
+}
+
+// TaskSpec is the desired state of a task.
+type TaskSpec struct {
+	Name  string `json:"name,omitempty"`
+	Image string `json:"image"`
+	// Kind is Service (the default) for tasks that run until stopped, or Job
+	// for tasks that run to completion.
+	Kind string `json:"kind,omitempty"`
+	// Cpu is the number of CPUs the container may use.
+	Cpu float64 `json:"cpu,omitempty"`
+	// Memory and Disk are limits in bytes.
+	Memory int64 `json:"memory,omitempty"`
+	Disk   int64 `json:"disk,omitempty"`
+	// Env holds environment variables in KEY=VALUE form.
+	Env []string `json:"env,omitempty"`
+	// Ports are the exposed container ports, e.g. "80/tcp".
+	Ports []string `json:"ports,omitempty"`
+	// PortBindings maps container ports to host ports.
+	PortBindings    map[string]string `json:"portBindings,omitempty"`
+	RestartPolicy   string            `json:"restartPolicy,omitempty"`
+	PullPolicy      string            `json:"pullPolicy,omitempty"`
+	ImagePullSecret string            `json:"imagePullSecret,omitempty"`
+	StopSignal      string            `json:"stopSignal,omitempty"`
+	// StopGracePeriod, ActiveDeadline and TTLAfterFinished are durations such
+	// as "30s" or "1h30m".
+	StopGracePeriod  string            `json:"stopGracePeriod,omitempty"`
+	PreStop          *Hook             `json:"preStop,omitempty"`
+	HealthCheck      *Hook             `json:"healthCheck,omitempty"`
+	ActiveDeadline   string            `json:"activeDeadline,omitempty"`
+	BackoffLimit     int               `json:"backoffLimit,omitempty"`
+	TTLAfterFinished string            `json:"ttlAfterFinished,omitempty"`
+	Artifacts        []Artifact        `json:"artifacts,omitempty"`
+	OutputsFile      string            `json:"outputsFile,omitempty"`
+	Labels           map[string]string `json:"labels,omitempty"`
+}
+
+// Hook is a command run in the container or an HTTP request to one of its ports.
+type Hook struct {
+	Exec []string  `json:"exec,omitempty"`
+	HTTP *HTTPHook `json:"http,omitempty"`
+}
+
+// HTTPHook is an HTTP request to a port of the container.
+type HTTPHook struct {
+	Port   string `json:"port"`
+	Path   string `json:"path"`
+	Method string `json:"method,omitempty"`
+}
+
+// Artifact is a file or directory copied out of the container of a completed job.
+type Artifact struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// Task is a task and its observed status.
+type Task struct {
+	ID     string     `json:"id"`
+	Spec   TaskSpec   `json:"spec"`
+	Status TaskStatus `json:"status"`
+}
+
+// TaskStatus is the observed state of a task.
+type TaskStatus struct {
+	State string `json:"state"`
+	// Worker is the address of the worker the task is assigned to.
+	Worker      string            `json:"worker,omitempty"`
+	ContainerID string            `json:"containerId,omitempty"`
+	StartTime   *time.Time        `json:"startTime,omitempty"`
+	EndTime     *time.Time        `json:"endTime,omitempty"`
+	ExitCode    int               `json:"exitCode,omitempty"`
+	Reason      string            `json:"reason,omitempty"`
+	Attempts    int               `json:"attempts,omitempty"`
+	Health      string            `json:"health,omitempty"`
+	StopOutcome string            `json:"stopOutcome,omitempty"`
+	Outputs     map[string]string `json:"outputs,omitempty"`
+	Usage       *Usage            `json:"usage,omitempty"`
+}
+
+// Usage is the latest resource utilization of a running container.
+type Usage struct {
+	Time time.Time `json:"time"`
+	// CPUPercent is the CPU used, where 100 is one full CPU.
+	CPUPercent      float64 `json:"cpuPercent"`
+	MemoryUsage     uint64  `json:"memoryUsage"`
+	MemoryPercent   float64 `json:"memoryPercent"`
+	NetworkRxBytes  uint64  `json:"networkRxBytes"`
+	NetworkTxBytes  uint64  `json:"networkTxBytes"`
+	BlockReadBytes  uint64  `json:"blockReadBytes"`
+	BlockWriteBytes uint64  `json:"blockWriteBytes"`
+}
+
+// TaskList is a page of tasks.
+type TaskList struct {
+	Items []Task `json:"items"`
+	// NextCursor is passed as the cursor parameter to get the next page; it is
+	// empty on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+	// Total is the number of matching tasks across all pages.
+	Total int `json:"total"`
+}
+
+// TaskEvent is a requested change of a task's state.
+type TaskEvent struct {
+	ID        string    `json:"id"`
+	TaskID    string    `json:"taskId"`
+	State     string    `json:"state"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// TaskEventList is the event history of a task, oldest first.
+type TaskEventList struct {
+	Items []TaskEvent `json:"items"`
+}
+
+// WatchEvent is a message of the GET /v1/tasks/watch stream.
+type WatchEvent struct {
+	Revision uint64 `json:"revision"`
+	// Type is TaskChanged, TaskDeleted or TaskEvent.
+	Type string `json:"type"`
+	// Task is the task after the change; only its ID is set for TaskDeleted.
+	Task  *Task      `json:"task,omitempty"`
+	Event *TaskEvent `json:"event,omitempty"`
+}
+
+// Node is a worker node of the cluster.
+type Node struct {
+	Name string `json:"name"`
+	Api  string `json:"api"`
+	Role string `json:"role"`
+	// Lost is true once the worker has stopped answering the manager.
+	Lost     bool      `json:"lost"`
+	LastSeen time.Time `json:"lastSeen"`
+	// Tasks is the number of tasks assigned to the worker.
+	Tasks int `json:"tasks"`
+}
+
+// NodeList is the list of worker nodes.
+type NodeList struct {
+	Items []Node `json:"items"`
+}
+
+// AdmissionRequest is the body of the requests the manager sends to admission
+// webhooks with each submitted task and task template.
+type AdmissionRequest struct {
+	// UID identifies the submission; it is the ID of the task event.
+	UID string `json:"uid"`
+	// TaskID is the ID of a submitted task; it is empty for a template.
+	TaskID string `json:"taskId,omitempty"`
+	// Template names the object whose task template is admitted, e.g.
+	// "Service/web" or "Workflow/etl"; it is empty for a task.
+	Template string   `json:"template,omitempty"`
+	Spec     TaskSpec `json:"spec"`
+}
+
+// AdmissionResponse is the body an admission webhook answers with.
+type AdmissionResponse struct {
+	// Allowed admits the task; if it is false the submission is rejected.
+	Allowed bool `json:"allowed"`
+	// Message explains why the task was rejected.
+	Message string `json:"message,omitempty"`
+	// Fields lists the rejected fields, named after the fields of AdmissionRequest,
+	// e.g. "spec.image".
+	Fields []handler.FieldError `json:"fields,omitempty"`
+	// Spec, if set by a mutating webhook, replaces the spec of the task.
+	Spec *TaskSpec `json:"spec,omitempty"`
+}
@@ -0,0 +1,283 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxBodySize bounds the size of a request body.
+const maxBodySize = 1 << 20
+
+// Validate checks the path and query parameters and the JSON body of a request
+// against an operation of the OpenAPI document, then decodes the body into v.
+//
+// Parameters:
+//   - r: The request; its path parameters are read from the chi route context
+//   - operationID: The operationId of the operation in the document
+//   - v: Where to decode the body; nil if the operation has no body
+//
+// Returns:
+//   - error: A *ValidationError listing every invalid field
+func Validate(r *http.Request, operationID string, v any) error {
+	op, ok := api.operations[operationID]
+	if !ok {
+		return fmt.Errorf("unknown operation %q", operationID)
+	}
+
+	var errs ValidationError
+	query := r.URL.Query()
+	for _, p := range op.params {
+		var values []string
+		switch p.in {
+		case "path":
+			if s := chi.URLParam(r, p.name); s != "" {
+				values = []string{s}
+			}
+		case "query":
+			values = query[p.name]
+		}
+		if len(values) == 0 {
+			if p.required {
+				errs.add(p.name, "is required")
+			}
+			continue
+		}
+		api.check(p.schema, api.paramValue(api.resolve(p.schema), values), p.name, &errs)
+	}
+
+	if op.body != nil && v != nil {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+		switch {
+		case err != nil:
+			errs.add("body", fmt.Sprintf("cannot be read: %v", err))
+		case len(body) > maxBodySize:
+			errs.add("body", fmt.Sprintf("must be at most %d bytes", maxBodySize))
+		case len(bytes.TrimSpace(body)) == 0:
+			if op.bodyRequired {
+				errs.add("body", "is required")
+			}
+		default:
+			d := json.NewDecoder(bytes.NewReader(body))
+			d.UseNumber()
+			var doc any
+			if err := d.Decode(&doc); err != nil {
+				errs.add("body", fmt.Sprintf("must be valid JSON: %v", err))
+				break
+			}
+			api.check(op.body, doc, "", &errs)
+			if len(errs.Fields) == 0 {
+				if err := json.Unmarshal(body, v); err != nil {
+					errs.add("body", err.Error())
+				}
+			}
+		}
+	}
+
+	if len(errs.Fields) > 0 {
+		return &errs
+	}
+	return nil
+}
+
+// paramValue converts the raw values of a parameter to the JSON value its
+// schema describes. Array parameters may be repeated or comma-separated.
+func (a *openAPI) paramValue(s schema, values []string) any {
+	if s["type"] == "array" {
+		var items []any
+		itemSchema := a.resolve(s["items"])
+		for _, v := range values {
+			for _, part := range strings.Split(v, ",") {
+				items = append(items, a.paramValue(itemSchema, []string{strings.TrimSpace(part)}))
+			}
+		}
+		return items
+	}
+
+	v := values[0]
+	switch s["type"] {
+	case "integer", "number":
+		return json.Number(v)
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+// check validates a JSON value decoded with UseNumber against a schema,
+// recording an error for every invalid field.
+func (a *openAPI) check(s schema, v any, field string, errs *ValidationError) {
+	s = a.resolve(s)
+	name := field
+	if name == "" {
+		name = "body"
+	}
+
+	if v == nil {
+		if nullable, _ := s["nullable"].(bool); !nullable && s["type"] != nil {
+			errs.add(name, "must not be null")
+		}
+		return
+	}
+
+	switch s["type"] {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			errs.add(name, "must be an object")
+			return
+		}
+		a.checkObject(s, obj, field, errs)
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			errs.add(name, "must be an array")
+			return
+		}
+		if n, ok := s["minItems"].(float64); ok && len(items) < int(n) {
+			errs.add(name, fmt.Sprintf("must have at least %d items", int(n)))
+		}
+		if n, ok := s["maxItems"].(float64); ok && len(items) > int(n) {
+			errs.add(name, fmt.Sprintf("must have at most %d items", int(n)))
+		}
+		for i, item := range items {
+			a.check(a.resolve(s["items"]), item, fieldIndex(name, i), errs)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			errs.add(name, "must be a string")
+			return
+		}
+		checkString(s, str, name, errs)
+	case "integer", "number":
+		n, ok := v.(json.Number)
+		if !ok {
+			errs.add(name, "must be a number")
+			return
+		}
+		checkNumber(s, n, name, errs)
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			errs.add(name, "must be true or false")
+			return
+		}
+	}
+
+	if enum, ok := s["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(v) }) {
+		allowed := make([]string, 0, len(enum))
+		for _, e := range enum {
+			if e != "" {
+				allowed = append(allowed, fmt.Sprint(e))
+			}
+		}
+		errs.add(name, "must be one of "+strings.Join(allowed, ", "))
+	}
+}
+
+func (a *openAPI) checkObject(s schema, obj map[string]any, field string, errs *ValidationError) {
+	props, _ := s["properties"].(map[string]any)
+	required, _ := s["required"].([]any)
+	for _, r := range required {
+		if _, ok := obj[r.(string)]; !ok {
+			errs.add(fieldKey(field, r.(string)), "is required")
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if p, ok := props[k]; ok {
+			a.check(a.resolve(p), obj[k], fieldKey(field, k), errs)
+			continue
+		}
+		switch extra := s["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				errs.add(fieldKey(field, k), "is not a known field")
+			}
+		case map[string]any:
+			a.check(a.resolve(extra), obj[k], fieldKey(field, k), errs)
+		}
+	}
+}
+
+func checkString(s schema, str, field string, errs *ValidationError) {
+	if n, ok := s["minLength"].(float64); ok && utf8.RuneCountInString(str) < int(n) {
+		if n == 1 {
+			errs.add(field, "must not be empty")
+		} else {
+			errs.add(field, fmt.Sprintf("must be at least %d characters", int(n)))
+		}
+	}
+	if n, ok := s["maxLength"].(float64); ok && utf8.RuneCountInString(str) > int(n) {
+		errs.add(field, fmt.Sprintf("must be at most %d characters", int(n)))
+	}
+	if p, ok := s["pattern"].(string); ok && !pattern(p).MatchString(str) {
+		errs.add(field, fmt.Sprintf("must match %s", p))
+	}
+
+	switch s["format"] {
+	case "uuid":
+		if _, err := uuid.Parse(str); err != nil {
+			errs.add(field, "must be a UUID")
+		}
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			errs.add(field, "must be an RFC 3339 time")
+		}
+	case "duration":
+		if _, err := time.ParseDuration(str); err != nil {
+			errs.add(field, "must be a duration such as 30s or 1h30m")
+		}
+	}
+}
+
+func checkNumber(s schema, n json.Number, field string, errs *ValidationError) {
+	f, err := n.Float64()
+	if err != nil {
+		errs.add(field, "must be a number")
+		return
+	}
+	if s["type"] == "integer" {
+		if _, err := n.Int64(); err != nil {
+			errs.add(field, "must be an integer")
+			return
+		}
+	}
+	if min, ok := s["minimum"].(float64); ok && f < min {
+		errs.add(field, fmt.Sprintf("must be at least %v", min))
+	}
+	if max, ok := s["maximum"].(float64); ok && f > max {
+		errs.add(field, fmt.Sprintf("must be at most %v", max))
+	}
+}
+
+// patterns caches the compiled patterns of the document.
+var patterns sync.Map
+
+func pattern(p string) *regexp.Regexp {
+	if re, ok := patterns.Load(p); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(p)
+	patterns.Store(p, re)
+	return re
+}
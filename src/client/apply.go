@@ -0,0 +1,23 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/utkarsh5026/Orchestra/manager"
+	"github.com/utkarsh5026/Orchestra/manifest"
+)
+
+// Apply converges the objects of the cluster to the declared ones; with
+// req.DryRun set it only returns the changes that would be made.
+//
+// Returns:
+//   - []manifest.Change: The change of every declared object and of every pruned one
+//   - error: An *Error with status 400 Bad Request if a declared object is invalid
+func (c *Client) Apply(ctx context.Context, req manager.ApplyRequest) ([]manifest.Change, error) {
+	var changes []manifest.Change
+	if err := c.do(ctx, http.MethodPost, "/apply", req, &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,151 @@
+// Package client is a Go client for the manager API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/utkarsh5026/Orchestra/utils"
+)
+
+// DefaultRetryOptions are the RetryOptions of a new Client.
+var DefaultRetryOptions = utils.HttpRetryOptions{
+	MaxRetries: 3,
+	WaitTime:   500 * time.Millisecond,
+}
+
+// Client calls the API of a manager. It is safe for concurrent use.
+type Client struct {
+	// BaseURL is the URL of the manager API, e.g. "http://localhost:5555".
+	BaseURL string
+	// HTTPClient sends the requests.
+	HTTPClient *http.Client
+	// RetryOptions controls how often idempotent requests are retried when the
+	// manager cannot be reached or is unavailable. The wait doubles after every attempt.
+	RetryOptions utils.HttpRetryOptions
+}
+
+// New creates a client for the manager at address, which is either a URL or a
+// host:port pair.
+func New(address string) *Client {
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	return &Client{
+		BaseURL:      strings.TrimSuffix(address, "/"),
+		HTTPClient:   http.DefaultClient,
+		RetryOptions: DefaultRetryOptions,
+	}
+}
+
+// do sends a request and decodes a JSON response into out.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the request, including retries
+//   - method: The HTTP method
+//   - path: The path of the endpoint, including any query string
+//   - in: The request body, encoded as JSON; nil sends no body
+//   - out: Where to decode the response body; nil discards it
+//
+// Returns:
+//   - error: If the manager cannot be reached, an *Error if it responded with
+//     an error, or an error if the response cannot be decoded
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	resp, err := c.send(ctx, method, path, in)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// send sends a request and returns its response if it succeeded; the caller
+// must close its body. GET, PUT and DELETE requests are retried according to
+// RetryOptions.
+func (c *Client) send(ctx context.Context, method, path string, in any) (*http.Response, error) {
+	var body []byte
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = b
+	}
+
+	attempts := 1
+	if idempotent(method) {
+		attempts = max(c.RetryOptions.MaxRetries, 1)
+	}
+	wait := c.RetryOptions.WaitTime
+
+	for i := 1; ; i++ {
+		resp, err := c.attempt(ctx, method, path, body)
+		if err == nil && !retryable(resp.StatusCode) {
+			if resp.StatusCode >= http.StatusBadRequest {
+				defer resp.Body.Close()
+				return nil, decodeError(resp)
+			}
+			return resp, nil
+		}
+
+		if err == nil {
+			if i >= attempts {
+				defer resp.Body.Close()
+				return nil, decodeError(resp)
+			}
+			resp.Body.Close()
+		} else if i >= attempts || ctx.Err() != nil {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, r)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach manager %s: %w", c.BaseURL, err)
+	}
+	return resp, nil
+}
+
+func idempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+}
+
+// retryable reports whether a response status means the manager or the worker
+// behind it may be available again shortly.
+func retryable(status int) bool {
+	return status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
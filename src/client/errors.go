@@ -0,0 +1,52 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/utkarsh5026/Orchestra/handler"
+)
+
+// Error is an error response of the manager.
+type Error struct {
+	handler.ResponseError
+}
+
+func (e *Error) Error() string {
+	if e.Details == "" {
+		return e.Message
+	}
+	return e.Message + ": " + e.Details
+}
+
+// IsNotFound reports whether err is an error response with status 404 Not Found.
+func IsNotFound(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == http.StatusNotFound
+}
+
+// IsGone reports whether err is an error response with status 410 Gone, as
+// returned by Watch when the events to resume from are no longer available.
+func IsGone(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == http.StatusGone
+}
+
+// decodeError turns an error response into an *Error, using the
+// handler.ResponseError in its body when there is one.
+func decodeError(resp *http.Response) error {
+	b, _ := io.ReadAll(resp.Body)
+
+	var e handler.ResponseError
+	if err := json.Unmarshal(b, &e); err != nil || e.Message == "" {
+		e = handler.Err(resp.StatusCode, resp.Status, nil)
+		if msg := bytes.TrimSpace(b); len(msg) > 0 {
+			e.Details = string(msg)
+		}
+	}
+	e.StatusCode = resp.StatusCode
+	return &Error{ResponseError: e}
+}
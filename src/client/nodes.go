@@ -0,0 +1,17 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	v1 "github.com/utkarsh5026/Orchestra/api/v1"
+)
+
+// ListNodes returns the status of every worker node of the cluster.
+func (c *Client) ListNodes(ctx context.Context) ([]v1.Node, error) {
+	var list v1.NodeList
+	if err := c.do(ctx, http.MethodGet, "/v1/nodes", nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
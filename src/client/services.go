@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/service"
+)
+
+// RollbackService restores the template of an earlier revision of a service
+// as a new revision.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the request
+//   - id: The ID of the service
+//   - revision: The revision to restore; zero means the one before the current one
+//
+// Returns:
+//   - *service.Service: The service at its new revision
+//   - error: If the service or revision does not exist
+func (c *Client) RollbackService(ctx context.Context, id uuid.UUID, revision int) (*service.Service, error) {
+	var svc service.Service
+	body := map[string]int{"Revision": revision}
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/services/%s/rollback", id), body, &svc); err != nil {
+		return nil, err
+	}
+	return &svc, nil
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+	v1 "github.com/utkarsh5026/Orchestra/api/v1"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// CreateTask submits a task to be scheduled on a worker. The manager assigns
+// an ID to a task without one.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the request
+//   - id: The ID of the new task; uuid.Nil lets the manager choose it
+//   - spec: The task to run
+//
+// Returns:
+//   - *v1.Task: The task as accepted by the manager
+//   - error: An *Error listing the invalid fields if the task is rejected, or
+//     an error if the manager cannot be reached
+func (c *Client) CreateTask(ctx context.Context, id uuid.UUID, spec v1.TaskSpec) (*v1.Task, error) {
+	req := v1.CreateTaskRequest{Spec: spec}
+	if id != uuid.Nil {
+		req.ID = id.String()
+	}
+
+	var created v1.Task
+	if err := c.do(ctx, http.MethodPost, "/v1/tasks", req, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetTask returns a task that has been scheduled on a worker.
+//
+// Returns:
+//   - *v1.Task: The task, including the worker it is assigned to
+//   - error: An *Error for which IsNotFound is true if the manager has no such task
+func (c *Client) GetTask(ctx context.Context, id uuid.UUID) (*v1.Task, error) {
+	var t v1.Task
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v1/tasks/%s", id), nil, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ListTasksOptions filters, orders and pages the tasks returned by ListTasks
+// and ListTasksPage.
+type ListTasksOptions struct {
+	task.Query
+	// Worker, if set, only returns the tasks assigned to the worker at this address.
+	Worker string
+}
+
+// values returns the query string of the options.
+func (o *ListTasksOptions) values() url.Values {
+	if o == nil {
+		return url.Values{}
+	}
+	v := o.Query.Values()
+	if o.Worker != "" {
+		v.Set("worker", o.Worker)
+	}
+	return v
+}
+
+// ListTasksPage returns one page of the tasks that have been scheduled on a
+// worker. The page after it is read by setting opts.Cursor to its NextCursor.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the request
+//   - opts: Filters, order and page to return; nil returns the first page of
+//     every task, most recently started first
+func (c *Client) ListTasksPage(ctx context.Context, opts *ListTasksOptions) (*v1.TaskList, error) {
+	path := "/v1/tasks"
+	if q := opts.values().Encode(); q != "" {
+		path += "?" + q
+	}
+
+	var list v1.TaskList
+	if err := c.do(ctx, http.MethodGet, path, nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// ListTasks returns every task that has been scheduled on a worker, following
+// the pages of the task list from opts.Cursor.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the requests
+//   - opts: Filters and order to apply; nil returns every task
+func (c *Client) ListTasks(ctx context.Context, opts *ListTasksOptions) ([]v1.Task, error) {
+	var o ListTasksOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Limit == 0 {
+		o.Limit = task.MaxPageSize
+	}
+
+	var tasks []v1.Task
+	for {
+		page, err := c.ListTasksPage(ctx, &o)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, page.Items...)
+		if page.NextCursor == "" {
+			return tasks, nil
+		}
+		o.Cursor = page.NextCursor
+	}
+}
+
+// StopTask asks the manager to stop the container of a task.
+func (c *Client) StopTask(ctx context.Context, id uuid.UUID) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/v1/tasks/%s", id), nil, nil)
+}
+
+// TaskEvents returns the events of a task that have been dispatched, oldest first.
+// An *Error for which IsNotFound is true is returned if the manager knows
+// neither the task nor any of its events.
+func (c *Client) TaskEvents(ctx context.Context, id uuid.UUID) ([]v1.TaskEvent, error) {
+	var list v1.TaskEventList
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v1/tasks/%s/events", id), nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// TaskLogs opens the output stream of a task's container as plain text.
+//
+// Returns:
+//   - io.ReadCloser: The log stream; the caller must close it
+//   - error: An *Error for which IsNotFound is true if the task has no container
+func (c *Client) TaskLogs(ctx context.Context, id uuid.UUID, opts task.LogOptions) (io.ReadCloser, error) {
+	q := url.Values{}
+	q.Set("follow", strconv.FormatBool(opts.Follow))
+	if opts.Tail != "" {
+		q.Set("tail", opts.Tail)
+	}
+
+	resp, err := c.send(ctx, http.MethodGet, fmt.Sprintf("/v1/tasks/%s/logs?%s", id, q.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,289 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/utkarsh5026/Orchestra/api/v1"
+	"github.com/utkarsh5026/Orchestra/handler"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// ChangeType is the kind of change reported by WatchTasks.
+type ChangeType string
+
+const (
+	TaskAdded    ChangeType = "Added"
+	TaskModified ChangeType = "Modified"
+	TaskDeleted  ChangeType = "Deleted"
+)
+
+// TaskChange is a change of a task observed by WatchTasks.
+type TaskChange struct {
+	Type ChangeType
+	// Task is the task after the change, or its last known state if it was deleted.
+	Task *v1.Task
+}
+
+// WatchOptions configures Watch.
+type WatchOptions struct {
+	// Revision, if Resume is set, is the revision of the last event seen by a
+	// previous watch; the events after it are replayed first.
+	Revision uint64
+	Resume   bool
+	// Task, if set, only watches the changes of this task.
+	Task uuid.UUID
+}
+
+// Watch streams the task changes and dispatched task events of the manager to
+// fn until ctx is cancelled or fn returns an error. If the stream breaks it is
+// reopened from the revision of the last event received, so no event is missed
+// or repeated.
+//
+// Parameters:
+//   - ctx: Context whose cancellation stops the watch
+//   - opts: Where to start and what to watch; nil watches every task from now
+//   - fn: Called with each event, in revision order
+//
+// Returns:
+//   - error: The error returned by fn, an error if the manager cannot be
+//     reached after the retries, an *Error for which IsGone is true if the
+//     events after the revision are no longer available, or ctx.Err() once
+//     ctx is cancelled
+func (c *Client) Watch(ctx context.Context, opts *WatchOptions, fn func(v1.WatchEvent) error) error {
+	return c.watch(ctx, opts, nil, fn)
+}
+
+// watch implements Watch, calling connected once the first stream is open and
+// before any of its events are read.
+func (c *Client) watch(ctx context.Context, opts *WatchOptions, connected func() error, fn func(v1.WatchEvent) error) error {
+	var o WatchOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	for {
+		resp, err := c.send(ctx, http.MethodGet, "/v1/tasks/watch?"+o.values().Encode(), nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		if !o.Resume {
+			o.Revision, _ = strconv.ParseUint(resp.Header.Get("X-Revision"), 10, 64)
+			o.Resume = true
+		}
+		if connected != nil {
+			err = connected()
+			connected = nil
+		}
+		if err == nil {
+			err = readEvents(resp, func(e v1.WatchEvent) error {
+				o.Revision = e.Revision
+				return fn(e)
+			})
+		}
+		resp.Body.Close()
+
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case !errors.Is(err, errStreamBroken):
+			return err
+		}
+
+		// The stream broke, e.g. because the manager restarted; reopen it from
+		// the last revision received once the manager can be reached again.
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.RetryOptions.WaitTime):
+		}
+	}
+}
+
+// values returns the query string of the options.
+func (o WatchOptions) values() url.Values {
+	v := url.Values{}
+	if o.Resume {
+		v.Set("revision", strconv.FormatUint(o.Revision, 10))
+	}
+	if o.Task != uuid.Nil {
+		v.Set("task", o.Task.String())
+	}
+	return v
+}
+
+// errStreamBroken is returned by readEvents when the stream ends or cannot be read.
+var errStreamBroken = errors.New("watch stream broken")
+
+// readEvents decodes the server-sent events of a watch stream and calls fn with
+// each of them.
+func readEvents(resp *http.Response, fn func(v1.WatchEvent) error) error {
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var data bytes.Buffer
+	for sc.Scan() {
+		line := sc.Bytes()
+		switch {
+		case len(line) == 0:
+			if data.Len() == 0 {
+				continue
+			}
+			var e v1.WatchEvent
+			if err := json.Unmarshal(data.Bytes(), &e); err != nil {
+				return fmt.Errorf("failed to decode watch event: %w", err)
+			}
+			data.Reset()
+			if err := fn(e); err != nil {
+				return err
+			}
+		case bytes.HasPrefix(line, []byte("data:")):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.Write(bytes.TrimPrefix(bytes.TrimPrefix(line, []byte("data:")), []byte(" ")))
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("%w: %w", errStreamBroken, err)
+	}
+	return errStreamBroken
+}
+
+// WatchTasks calls fn for every task known to the manager, then for every task
+// that is added, changes state, or is removed, until ctx is cancelled or fn
+// returns an error.
+//
+// Parameters:
+//   - ctx: Context whose cancellation stops the watch
+//   - fn: Called with each change, in the order they happen
+//
+// Returns:
+//   - error: The error returned by fn, the error of a request that failed after
+//     its retries, an *Error for which IsGone is true if the manager restarted
+//     and the watch cannot be resumed, or ctx.Err() once ctx is cancelled
+func (c *Client) WatchTasks(ctx context.Context, fn func(TaskChange) error) error {
+	known := make(map[string]*v1.Task)
+	list := func() error {
+		tasks, err := c.ListTasks(ctx, nil)
+		if err != nil {
+			return err
+		}
+		for i := range tasks {
+			t := &tasks[i]
+			known[t.ID] = t
+			if err := fn(TaskChange{Type: TaskAdded, Task: t}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return c.watch(ctx, nil, list, func(e v1.WatchEvent) error {
+		switch e.Type {
+		case watchTaskChanged:
+			_, ok := known[e.Task.ID]
+			known[e.Task.ID] = e.Task
+			if !ok {
+				return fn(TaskChange{Type: TaskAdded, Task: e.Task})
+			}
+			return fn(TaskChange{Type: TaskModified, Task: e.Task})
+		case watchTaskDeleted:
+			t, ok := known[e.Task.ID]
+			if !ok {
+				return nil
+			}
+			delete(known, e.Task.ID)
+			return fn(TaskChange{Type: TaskDeleted, Task: t})
+		}
+		return nil
+	})
+}
+
+// The types of watch events.
+const (
+	watchTaskChanged = "TaskChanged"
+	watchTaskDeleted = "TaskDeleted"
+)
+
+// done reports whether a task has reached a terminal state from which it will
+// not be retried.
+func done(t *v1.Task) bool {
+	switch t.Status.State {
+	case task.Completed.String():
+		return true
+	case task.Failed.String():
+		return t.Spec.Kind != string(task.KindJob) || t.Status.Attempts >= t.Spec.BackoffLimit
+	}
+	return false
+}
+
+// errTaskDone stops the watch of WaitTask.
+var errTaskDone = errors.New("task done")
+
+// WaitTask blocks until a task reaches a terminal state from which it will not
+// be retried. A task that is still queued and not known to the manager yet is
+// waited for until it is scheduled.
+//
+// Parameters:
+//   - ctx: Context whose cancellation stops the wait
+//   - id: The ID of the task
+//
+// Returns:
+//   - *v1.Task: The task in its final state
+//   - error: An *Error for which IsNotFound is true if the task is deleted
+//     before it finishes, or ctx.Err() once ctx is cancelled
+func (c *Client) WaitTask(ctx context.Context, id uuid.UUID) (*v1.Task, error) {
+	var final *v1.Task
+	check := func() error {
+		t, err := c.GetTask(ctx, id)
+		if IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if done(t) {
+			final = t
+			return errTaskDone
+		}
+		return nil
+	}
+
+	for {
+		err := c.watch(ctx, &WatchOptions{Task: id}, check, func(e v1.WatchEvent) error {
+			switch e.Type {
+			case watchTaskChanged:
+				if done(e.Task) {
+					final = e.Task
+					return errTaskDone
+				}
+			case watchTaskDeleted:
+				return &Error{ResponseError: handler.Err(http.StatusNotFound, "Task was deleted", nil)}
+			}
+			return nil
+		})
+		switch {
+		case errors.Is(err, errTaskDone):
+			return final, nil
+		case IsGone(err):
+			// The manager restarted and lost the revisions; check the task again.
+			continue
+		default:
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/utkarsh5026/Orchestra/manager"
+	"github.com/utkarsh5026/Orchestra/manifest"
+)
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	addManagerFlag(applyCmd)
+	addManifestFlags(applyCmd)
+	applyCmd.Flags().Bool("dry-run", false, "Only print what would change")
+
+	rootCmd.AddCommand(diffCmd)
+	addManagerFlag(diffCmd)
+	addManifestFlags(diffCmd)
+}
+
+func addManifestFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayP("filename", "f", nil, "Manifest file, directory of .yaml/.yml files, or - for stdin (repeatable)")
+	cmd.Flags().Bool("prune", false, "Delete objects created from earlier manifests that are no longer declared")
+	_ = cmd.MarkFlagRequired("filename")
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply -f <manifest>",
+	Short: "Create, update or delete objects to match manifest files.",
+	Long: `Applies YAML manifests declaring tasks, services, cron jobs and workflows.
+The manager compares them with the current objects, matched by kind and name,
+and creates, updates or replaces them accordingly. With --prune, objects
+created from earlier manifests that are no longer declared are deleted.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		changes, err := applyManifests(cmd, dryRun)
+		if err != nil {
+			return err
+		}
+
+		suffix := ""
+		if dryRun {
+			suffix = " (dry run)"
+		}
+		for _, c := range changes {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s %s%s\n", c.Key(), pastTense[c.Action], suffix)
+		}
+		return nil
+	},
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff -f <manifest>",
+	Short: "Show what applying manifest files would change.",
+	Long:  `Prints the objects that apply would create (+), update (~), replace (-/+) or delete (-), with the fields that differ.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		changes, err := applyManifests(cmd, true)
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		for _, c := range changes {
+			if c.Action == manifest.ActionUnchanged {
+				continue
+			}
+			fmt.Fprintf(out, "%s %s\n", diffMarks[c.Action], c.Key())
+			for _, f := range c.Fields {
+				fmt.Fprintf(out, "    %s: %s -> %s\n", f.Path, diffValue(f.Current), diffValue(f.Desired))
+			}
+		}
+		return nil
+	},
+}
+
+var pastTense = map[manifest.Action]string{
+	manifest.ActionCreate:    "created",
+	manifest.ActionUpdate:    "updated",
+	manifest.ActionReplace:   "replaced",
+	manifest.ActionDelete:    "deleted",
+	manifest.ActionUnchanged: "unchanged",
+}
+
+var diffMarks = map[manifest.Action]string{
+	manifest.ActionCreate:  "+",
+	manifest.ActionUpdate:  "~",
+	manifest.ActionReplace: "-/+",
+	manifest.ActionDelete:  "-",
+}
+
+// applyManifests reads the manifests named by the --filename flags and sends
+// them to the manager.
+func applyManifests(cmd *cobra.Command, dryRun bool) ([]manifest.Change, error) {
+	files, _ := cmd.Flags().GetStringArray("filename")
+	prune, _ := cmd.Flags().GetBool("prune")
+
+	var objs []manifest.Object
+	for _, f := range files {
+		o, err := readManifests(cmd, f)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, o...)
+	}
+	if err := manifest.Validate(objs); err != nil {
+		return nil, err
+	}
+
+	req := manager.ApplyRequest{Objects: objs, Prune: prune, DryRun: dryRun}
+	changes, err := newClient(cmd).Apply(cmd.Context(), req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply manifests: %w", err)
+	}
+	return changes, nil
+}
+
+// readManifests parses a manifest file, every .yaml and .yml file of a
+// directory in lexical order, or stdin if path is "-".
+func readManifests(cmd *cobra.Command, path string) ([]manifest.Object, error) {
+	if path == "-" {
+		return parseManifest("stdin", cmd.InOrStdin())
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{path}
+	if info.IsDir() {
+		paths = nil
+		for _, pattern := range []string{"*.yaml", "*.yml"} {
+			matches, err := filepath.Glob(filepath.Join(path, pattern))
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, matches...)
+		}
+		slices.Sort(paths)
+	}
+
+	var objs []manifest.Object
+	for _, p := range paths {
+		f, err := os.Open(p)
+		if err != nil {
+			return nil, err
+		}
+		o, err := parseManifest(p, f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, o...)
+	}
+	return objs, nil
+}
+
+func parseManifest(name string, r io.Reader) ([]manifest.Object, error) {
+	objs, err := manifest.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return objs, nil
+}
+
+// diffValue formats a field value of a diff as JSON, or "<none>" if it is unset.
+func diffValue(v any) string {
+	if v == nil {
+		return "<none>"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/utkarsh5026/Orchestra/client"
+)
+
+// addManagerFlag adds the --manager flag naming the manager API that the
+// subcommands of cmd talk to.
+func addManagerFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP("manager", "m", "localhost:5555", "Address of the manager API")
+}
+
+// newClient returns a client for the manager named by the --manager flag of cmd.
+func newClient(cmd *cobra.Command) *client.Client {
+	manager, _ := cmd.Flags().GetString("manager")
+	return client.New(manager)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/utkarsh5026/Orchestra/manager"
+	"github.com/utkarsh5026/Orchestra/scheduler"
+)
+
+func init() {
+	rootCmd.AddCommand(managerCmd)
+	managerCmd.Flags().StringP("host", "H", "0.0.0.0", "Hostname or IP address")
+	managerCmd.Flags().UintP("port", "p", 5555, "Port on which to listen")
+	managerCmd.Flags().StringSliceP("workers", "w", []string{"localhost:5556"}, "Addresses (host:port) of the workers to schedule tasks on")
+	managerCmd.Flags().StringP("scheduler", "s", "roundrobin", "Scheduler used to place tasks on workers (only \"roundrobin\" is supported)")
+	managerCmd.Flags().StringP("dbtype", "d", "memory", "Type of datastore to use for tasks and events (only \"memory\" is supported)")
+	managerCmd.Flags().Duration("poll-interval", 15*time.Second, "How often workers are polled for the state of their tasks")
+	managerCmd.Flags().Duration("reconcile-interval", 10*time.Second, "How often workflows, cron jobs and services are reconciled")
+	managerCmd.Flags().Duration("autoscale-interval", 30*time.Second, "How often autoscaled services are evaluated")
+	managerCmd.Flags().String("admission-config", "", "YAML file declaring the admission webhooks called with submitted tasks")
+}
+
+var managerCmd = &cobra.Command{
+	Use:   "manager",
+	Short: "Manager command to operate an Orchestra manager node.",
+	Long: `Runs the manager. The manager accepts tasks, workflows, cron jobs and services
+through its API, schedules tasks onto the given workers and keeps track of their state.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		host, _ := cmd.Flags().GetString("host")
+		port, _ := cmd.Flags().GetUint("port")
+		workers, _ := cmd.Flags().GetStringSlice("workers")
+		schedulerName, _ := cmd.Flags().GetString("scheduler")
+		dbType, _ := cmd.Flags().GetString("dbtype")
+		pollInterval, _ := cmd.Flags().GetDuration("poll-interval")
+		reconcileInterval, _ := cmd.Flags().GetDuration("reconcile-interval")
+		autoscaleInterval, _ := cmd.Flags().GetDuration("autoscale-interval")
+		admissionConfig, _ := cmd.Flags().GetString("admission-config")
+
+		if len(workers) == 0 {
+			return fmt.Errorf("at least one worker is required")
+		}
+		sched, err := schedulerType(schedulerName)
+		if err != nil {
+			return err
+		}
+		st, err := storeType(dbType)
+		if err != nil {
+			return err
+		}
+		var webhooks []manager.Webhook
+		if admissionConfig != "" {
+			if webhooks, err = manager.LoadWebhooks(admissionConfig); err != nil {
+				return err
+			}
+		}
+
+		shutdownTracing, err := setupTracing(cmd, "orchestra-manager")
+		if err != nil {
+			return err
+		}
+		defer flushTracing(shutdownTracing)
+
+		m := manager.NewManager(workers, sched, st)
+		m.Webhooks = webhooks
+		api := &manager.Api{Address: host, Port: port, Manager: m}
+		slog.Info("Starting manager", "workers", workers, "webhooks", len(webhooks))
+
+		return serve(cmd.Context(), api,
+			m.LoopTasks,
+			func(ctx context.Context) { m.PollWorkers(ctx, pollInterval) },
+			func(ctx context.Context) { m.UpdateWorkflows(ctx, reconcileInterval) },
+			func(ctx context.Context) { m.RunCronJobs(ctx, reconcileInterval) },
+			func(ctx context.Context) { m.ReconcileServices(ctx, reconcileInterval) },
+			func(ctx context.Context) { m.AutoscaleServices(ctx, autoscaleInterval) },
+		)
+	},
+}
+
+// schedulerType maps the value of a --scheduler flag to a scheduler.Type.
+func schedulerType(name string) (scheduler.Type, error) {
+	switch name {
+	case "roundrobin":
+		return scheduler.RoundRobinScheduler, nil
+	default:
+		return 0, fmt.Errorf("unsupported scheduler %q", name)
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(nodeCmd)
+	addManagerFlag(nodeCmd)
+
+	nodeCmd.AddCommand(nodeListCmd)
+	addOutputFlag(nodeListCmd)
+}
+
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Inspect the worker nodes of the cluster.",
+}
+
+var nodeListCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List worker nodes.",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		nodes, err := newClient(cmd).ListNodes(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("failed to list nodes: %w", err)
+		}
+
+		return printOutput(cmd, nodes, func(w io.Writer) {
+			fmt.Fprintln(w, "NAME\tSTATUS\tTASKS\tLAST SEEN")
+			for _, n := range nodes {
+				status := "Ready"
+				if n.Lost {
+					status = "Lost"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", n.Name, status, n.Tasks, ago(n.LastSeen))
+			}
+		})
+	},
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by the --output flag.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// addOutputFlag adds the --output flag selecting how a command prints its result.
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("output", "o", outputTable, "Output format (\"table\", \"json\" or \"yaml\")")
+}
+
+// printOutput writes v in the format selected by the --output flag of cmd. The
+// table format is written by table, which receives a tab-separated writer.
+func printOutput(cmd *cobra.Command, v any, table func(w io.Writer)) error {
+	format, _ := cmd.Flags().GetString("output")
+	out := cmd.OutOrStdout()
+
+	switch format {
+	case outputTable:
+		tw := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		table(tw)
+		return tw.Flush()
+	case outputJSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case outputYAML:
+		return writeYAML(out, v)
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+// writeYAML writes v as YAML with the same field names and order as its JSON
+// encoding, so that both formats describe objects the same way.
+func writeYAML(w io.Writer, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(b, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// blockStyle clears the flow and quoting styles that decoding JSON leaves on n
+// and its children.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
@@ -1,20 +1,37 @@
 package cmd
 
 import (
-	"github.com/spf13/cobra"
-	"log"
+	"fmt"
 	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/utkarsh5026/Orchestra/logging"
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "Orch",
 	Short: "Orch is a CLI tool to manage your tasks in a clustered environment",
+	// Errors are printed by Start.
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Arguments and flags are valid once the command runs, so its errors
+		// are not usage errors.
+		cmd.SilenceUsage = true
+		level, _ := cmd.Flags().GetString("log-level")
+		format, _ := cmd.Flags().GetString("log-format")
+		return logging.Setup(os.Stderr, level, logging.Format(format))
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().String("log-level", "info", "Minimum log level (\"debug\", \"info\", \"warn\" or \"error\")")
+	rootCmd.PersistentFlags().String("log-format", string(logging.FormatText), "Log format (\"text\" or \"json\")")
 }
 
 func Start() {
 	err := rootCmd.Execute()
 	if err != nil {
-		log.Println(err)
+		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout bounds how long a server waits for in-flight requests on shutdown.
+const shutdownTimeout = 10 * time.Second
+
+// server is the API of a manager or worker.
+type server interface {
+	Start() error
+	Shutdown(ctx context.Context) error
+}
+
+// serve runs srv and the background loops until SIGINT or SIGTERM is received
+// or the server fails, then shuts the server down and waits for the loops to
+// return.
+//
+// Parameters:
+//   - ctx: The parent context; cancelling it also triggers shutdown
+//   - srv: The API server to run
+//   - loops: Background loops that must return once their context is cancelled
+//
+// Returns:
+//   - error: If the server failed or could not be shut down cleanly
+func serve(ctx context.Context, srv server, loops ...func(context.Context)) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var wg sync.WaitGroup
+	for _, loop := range loops {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			loop(ctx)
+		}()
+	}
+
+	serverErr := make(chan error, 1)
+	go func() { serverErr <- srv.Start() }()
+
+	var err error
+	select {
+	case <-ctx.Done():
+		slog.Info("Shutting down")
+	case err = <-serverErr:
+		slog.Error("API server stopped", "error", err)
+	}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if shutdownErr := srv.Shutdown(shutdownCtx); shutdownErr != nil {
+		err = errors.Join(err, shutdownErr)
+	}
+
+	wg.Wait()
+	return err
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(serviceCmd)
+	addManagerFlag(serviceCmd)
+
+	serviceCmd.AddCommand(serviceRollbackCmd)
+	serviceRollbackCmd.Flags().IntP("revision", "r", 0, "Revision to roll back to (defaults to the previous revision)")
+}
+
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "Manage replicated services.",
+}
+
+var serviceRollbackCmd = &cobra.Command{
+	Use:   "rollback <service-id>",
+	Short: "Roll a service back to a previous revision.",
+	Long:  `Restores the template of a previous revision of the service as a new revision and replaces its tasks with a rolling update.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		revision, _ := cmd.Flags().GetInt("revision")
+
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid service ID: %w", err)
+		}
+
+		svc, err := newClient(cmd).RollbackService(cmd.Context(), id, revision)
+		if err != nil {
+			return fmt.Errorf("rollback failed: %w", err)
+		}
+		fmt.Printf("Service %s rolled back, now at revision %d\n", svc.Name, svc.Revision)
+		return nil
+	},
+}
@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	v1 "github.com/utkarsh5026/Orchestra/api/v1"
+	"github.com/utkarsh5026/Orchestra/client"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+func init() {
+	rootCmd.AddCommand(taskCmd)
+	addManagerFlag(taskCmd)
+
+	taskCmd.AddCommand(taskRunCmd)
+	taskRunCmd.Flags().String("name", "", "Name of the task (defaults to the image name followed by part of the task ID)")
+	taskRunCmd.Flags().Float64("cpu", 0, "Number of CPUs the container may use, e.g. 0.5")
+	taskRunCmd.Flags().String("memory", "", "Memory limit of the container, e.g. 256m or 1g")
+	taskRunCmd.Flags().StringArrayP("env", "e", nil, "Environment variable of the container in KEY=VALUE form (repeatable)")
+	taskRunCmd.Flags().StringSliceP("port", "p", nil, "Container port to expose, e.g. 80 or 53/udp (repeatable)")
+	taskRunCmd.Flags().StringToStringP("label", "l", nil, "Label of the task in KEY=VALUE form (repeatable)")
+
+	taskCmd.AddCommand(taskListCmd)
+	taskListCmd.Flags().StringSliceP("state", "s", nil, "Only list tasks in these states, e.g. running (repeatable)")
+	taskListCmd.Flags().StringP("selector", "l", "", "Only list tasks whose labels match this selector, e.g. app=web,tier!=db")
+	taskListCmd.Flags().String("worker", "", "Only list tasks assigned to the worker at this address")
+	taskListCmd.Flags().String("name-prefix", "", "Only list tasks whose name starts with this prefix")
+	taskListCmd.Flags().String("sort", string(task.SortByStartTime), "Field to sort by: start_time, end_time, name, state or id")
+	taskListCmd.Flags().String("order", "", "Sort order, asc or desc (defaults to desc for times and asc otherwise)")
+	addOutputFlag(taskListCmd)
+
+	taskCmd.AddCommand(taskDescribeCmd)
+	addOutputFlag(taskDescribeCmd)
+
+	taskCmd.AddCommand(taskStopCmd)
+
+	taskCmd.AddCommand(taskWaitCmd)
+	taskWaitCmd.Flags().Duration("timeout", 0, "Give up after this long, e.g. 10m (defaults to waiting indefinitely)")
+
+	taskCmd.AddCommand(taskLogsCmd)
+	taskLogsCmd.Flags().BoolP("follow", "f", false, "Keep streaming new output until the container exits")
+	taskLogsCmd.Flags().String("tail", "", "Number of lines to show from the end of the output (defaults to all)")
+}
+
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Run and inspect tasks.",
+}
+
+var taskRunCmd = &cobra.Command{
+	Use:   "run <image>",
+	Short: "Run a container image as a task.",
+	Long:  `Submits a task running the given image to the manager, which schedules it on a worker.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		cpu, _ := cmd.Flags().GetFloat64("cpu")
+		memory, _ := cmd.Flags().GetString("memory")
+		env, _ := cmd.Flags().GetStringArray("env")
+		ports, _ := cmd.Flags().GetStringSlice("port")
+		labels, _ := cmd.Flags().GetStringToString("label")
+
+		id := uuid.New()
+		spec := v1.TaskSpec{
+			Name:   name,
+			Image:  args[0],
+			Cpu:    cpu,
+			Env:    env,
+			Ports:  ports,
+			Labels: labels,
+		}
+		if spec.Name == "" {
+			spec.Name = fmt.Sprintf("%s-%s", imageName(spec.Image), id.String()[:8])
+		}
+		if memory != "" {
+			m, err := units.RAMInBytes(memory)
+			if err != nil {
+				return fmt.Errorf("invalid memory %q: %w", memory, err)
+			}
+			spec.Memory = m
+		}
+
+		created, err := newClient(cmd).CreateTask(cmd.Context(), id, spec)
+		if err != nil {
+			return fmt.Errorf("failed to submit task: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), created.ID)
+		return nil
+	},
+}
+
+var taskListCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List tasks.",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := url.Values{}
+		for _, flag := range []string{"selector", "sort", "order"} {
+			if v, _ := cmd.Flags().GetString(flag); v != "" {
+				params.Set(flag, v)
+			}
+		}
+		if prefix, _ := cmd.Flags().GetString("name-prefix"); prefix != "" {
+			params.Set("name_prefix", prefix)
+		}
+		params["state"], _ = cmd.Flags().GetStringSlice("state")
+
+		q, err := task.ParseQuery(params)
+		if err != nil {
+			return err
+		}
+		opts := client.ListTasksOptions{Query: q}
+		opts.Worker, _ = cmd.Flags().GetString("worker")
+
+		tasks, err := newClient(cmd).ListTasks(cmd.Context(), &opts)
+		if err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+
+		return printOutput(cmd, tasks, func(w io.Writer) {
+			fmt.Fprintln(w, "ID\tNAME\tIMAGE\tSTATE\tSTARTED")
+			for _, t := range tasks {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.ID, t.Spec.Name, t.Spec.Image, t.Status.State, ago(deref(t.Status.StartTime)))
+			}
+		})
+	},
+}
+
+// taskDescription is the output of task describe.
+type taskDescription struct {
+	Task   *v1.Task
+	Events []v1.TaskEvent
+}
+
+var taskDescribeCmd = &cobra.Command{
+	Use:   "describe <task-id>",
+	Short: "Show the details and events of a task.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid task ID: %w", err)
+		}
+
+		c := newClient(cmd)
+		var d taskDescription
+		if d.Task, err = c.GetTask(cmd.Context(), id); err != nil {
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+		if d.Events, err = c.TaskEvents(cmd.Context(), id); err != nil {
+			return fmt.Errorf("failed to get task events: %w", err)
+		}
+
+		return printOutput(cmd, d, func(w io.Writer) {
+			describeTask(w, d)
+		})
+	},
+}
+
+// describeTask writes the details of a task followed by its events.
+func describeTask(w io.Writer, d taskDescription) {
+	spec, status := d.Task.Spec, d.Task.Status
+	fmt.Fprintf(w, "ID:\t%s\n", d.Task.ID)
+	fmt.Fprintf(w, "Name:\t%s\n", spec.Name)
+	fmt.Fprintf(w, "Image:\t%s\n", spec.Image)
+	fmt.Fprintf(w, "State:\t%s\n", status.State)
+	fmt.Fprintf(w, "Worker:\t%s\n", orNone(status.Worker))
+	if status.Reason != "" {
+		fmt.Fprintf(w, "Reason:\t%s\n", status.Reason)
+	}
+	if status.State == task.Completed.String() || status.State == task.Failed.String() {
+		fmt.Fprintf(w, "Exit Code:\t%d\n", status.ExitCode)
+	}
+	fmt.Fprintf(w, "Container:\t%s\n", orNone(status.ContainerID))
+	fmt.Fprintf(w, "CPU:\t%g\n", spec.Cpu)
+	fmt.Fprintf(w, "Memory:\t%s\n", units.BytesSize(float64(spec.Memory)))
+	fmt.Fprintf(w, "Started:\t%s\n", ago(deref(status.StartTime)))
+	fmt.Fprintf(w, "Finished:\t%s\n", ago(deref(status.EndTime)))
+	for k, v := range spec.Labels {
+		fmt.Fprintf(w, "Label:\t%s=%s\n", k, v)
+	}
+	for _, p := range spec.Ports {
+		fmt.Fprintf(w, "Port:\t%s\n", p)
+	}
+	for _, e := range spec.Env {
+		fmt.Fprintf(w, "Env:\t%s\n", e)
+	}
+
+	fmt.Fprintln(w, "\nEvents:")
+	if len(d.Events) == 0 {
+		fmt.Fprintln(w, "  <none>")
+		return
+	}
+	fmt.Fprintln(w, "  TIME\tEVENT\tSTATE")
+	for _, e := range d.Events {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", e.Timestamp.Format(time.RFC3339), e.ID, e.State)
+	}
+}
+
+var taskStopCmd = &cobra.Command{
+	Use:   "stop <task-id>",
+	Short: "Stop a task.",
+	Long:  `Asks the manager to stop the container of the task on its worker.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid task ID: %w", err)
+		}
+
+		if err := newClient(cmd).StopTask(cmd.Context(), id); err != nil {
+			return fmt.Errorf("failed to stop task: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Task %s stop requested\n", id)
+		return nil
+	},
+}
+
+var taskWaitCmd = &cobra.Command{
+	Use:   "wait <task-id>...",
+	Short: "Wait for tasks to finish.",
+	Long: `Blocks until every task has completed or failed without retries left, using the
+manager's watch stream, and prints the final state of each. Exits with an error
+if any of the tasks failed.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids := make([]uuid.UUID, len(args))
+		for i, arg := range args {
+			id, err := uuid.Parse(arg)
+			if err != nil {
+				return fmt.Errorf("invalid task ID %q: %w", arg, err)
+			}
+			ids[i] = id
+		}
+
+		ctx := cmd.Context()
+		if timeout, _ := cmd.Flags().GetDuration("timeout"); timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		c := newClient(cmd)
+		var failed []string
+		for _, id := range ids {
+			t, err := c.WaitTask(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to wait for task %s: %w", id, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\n", t.ID, t.Status.State)
+			if t.Status.State == task.Failed.String() {
+				failed = append(failed, t.ID)
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("tasks failed: %s", strings.Join(failed, ", "))
+		}
+		return nil
+	},
+}
+
+var taskLogsCmd = &cobra.Command{
+	Use:   "logs <task-id>",
+	Short: "Print the output of a task's container.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := uuid.Parse(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid task ID: %w", err)
+		}
+		follow, _ := cmd.Flags().GetBool("follow")
+		tail, _ := cmd.Flags().GetString("tail")
+
+		logs, err := newClient(cmd).TaskLogs(cmd.Context(), id, task.LogOptions{Follow: follow, Tail: tail})
+		if err != nil {
+			return fmt.Errorf("failed to get task logs: %w", err)
+		}
+		defer logs.Close()
+
+		_, err = io.Copy(cmd.OutOrStdout(), logs)
+		return err
+	},
+}
+
+// imageName returns the repository name of an image reference without its
+// registry, path and tag, e.g. "nginx" for "docker.io/library/nginx:1.27".
+func imageName(image string) string {
+	name := image[strings.LastIndex(image, "/")+1:]
+	if i := strings.IndexAny(name, ":@"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// deref returns the time t points to, or the zero time if t is nil.
+func deref(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// ago formats t relative to now, or "-" if it is unset.
+func ago(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return units.HumanDuration(time.Since(t)) + " ago"
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
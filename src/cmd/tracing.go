@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+	"github.com/utkarsh5026/Orchestra/tracing"
+)
+
+func init() {
+	rootCmd.PersistentFlags().String("trace-exporter", string(tracing.ExporterNone),
+		"Where to export traces (\"none\", \"stdout\" or \"otlp\"); otlp honours the OTEL_EXPORTER_OTLP_* environment variables")
+}
+
+// setupTracing installs the tracer provider selected by the --trace-exporter flag.
+// The returned function flushes pending spans and must be called on shutdown.
+func setupTracing(cmd *cobra.Command, serviceName string) (func(context.Context) error, error) {
+	exp, _ := cmd.Flags().GetString("trace-exporter")
+	return tracing.Setup(cmd.Context(), serviceName, tracing.Exporter(exp))
+}
+
+// flushTracing runs the shutdown function returned by setupTracing with a bounded timeout.
+func flushTracing(shutdown func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		slog.Error("Error flushing traces", "error", err)
+	}
+}
@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
+	"github.com/utkarsh5026/Orchestra/logging"
+	"github.com/utkarsh5026/Orchestra/store"
+	"github.com/utkarsh5026/Orchestra/task"
+	"github.com/utkarsh5026/Orchestra/worker"
 )
 
 func init() {
@@ -12,11 +19,82 @@ func init() {
 	workerCmd.Flags().StringP("host", "H", "0.0.0.0", "Hostname or IP address")
 	workerCmd.Flags().IntP("port", "p", 5556, "Port on which to listen")
 	workerCmd.Flags().StringP("name", "n", fmt.Sprintf("worker-%s", uuid.New().String()), "Name of the worker")
-	workerCmd.Flags().StringP("dbtype", "d", "memory", "Type of datastore to use for tasks (\"memory\" or \"persistent\")")
+	workerCmd.Flags().StringP("dbtype", "d", "memory", "Type of datastore to use for tasks (only \"memory\" is supported)")
+	workerCmd.Flags().Duration("update-interval", 15*time.Second, "How often the state of running containers is checked")
+	workerCmd.Flags().Duration("monitor-interval", 30*time.Second, "How often the Docker daemon connection is checked")
+	workerCmd.Flags().StringSlice("peers", nil, "Addresses (host:port) of the other workers, as given to the manager, that input artifacts may be fetched from")
+	workerCmd.Flags().String("registry-config", "", "Docker config.json style file with the credentials used to pull images from private registries")
+
+	timeouts := task.DefaultTimeouts()
+	workerCmd.Flags().Duration("pull-timeout", timeouts.Pull, "How long pulling an image may take (0 for no limit)")
+	workerCmd.Flags().Duration("create-timeout", timeouts.Create, "How long creating a container may take (0 for no limit)")
+	workerCmd.Flags().Duration("start-timeout", timeouts.Start, "How long starting a container may take (0 for no limit)")
+	workerCmd.Flags().Duration("stop-timeout", timeouts.Stop, "How long removing a stopped container may take (0 for no limit)")
 }
 
 var workerCmd = &cobra.Command{
 	Use:   "worker",
-	Short: "Worker command to operate a Cube worker node.",
-	Long:  `cube worker command.The worker runs tasks and responds to the manager's requests about task state.`,
+	Short: "Worker command to operate an Orchestra worker node.",
+	Long:  `Runs the worker. The worker runs tasks and responds to the manager's requests about task state.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		host, _ := cmd.Flags().GetString("host")
+		port, _ := cmd.Flags().GetInt("port")
+		name, _ := cmd.Flags().GetString("name")
+		dbType, _ := cmd.Flags().GetString("dbtype")
+		updateInterval, _ := cmd.Flags().GetDuration("update-interval")
+		monitorInterval, _ := cmd.Flags().GetDuration("monitor-interval")
+		registryConfig, _ := cmd.Flags().GetString("registry-config")
+		peers, _ := cmd.Flags().GetStringSlice("peers")
+		var timeouts task.Timeouts
+		timeouts.Pull, _ = cmd.Flags().GetDuration("pull-timeout")
+		timeouts.Create, _ = cmd.Flags().GetDuration("create-timeout")
+		timeouts.Start, _ = cmd.Flags().GetDuration("start-timeout")
+		timeouts.Stop, _ = cmd.Flags().GetDuration("stop-timeout")
+		if timeouts.Pull < 0 || timeouts.Create < 0 || timeouts.Start < 0 || timeouts.Stop < 0 {
+			return fmt.Errorf("docker timeouts must not be negative")
+		}
+
+		st, err := storeType(dbType)
+		if err != nil {
+			return err
+		}
+
+		shutdownTracing, err := setupTracing(cmd, "orchestra-worker")
+		if err != nil {
+			return err
+		}
+		defer flushTracing(shutdownTracing)
+
+		w, err := worker.NewWorker(name, st)
+		if err != nil {
+			return err
+		}
+		defer w.Docker.Close()
+		w.Docker.Timeouts = timeouts
+		w.Peers = peers
+		if registryConfig != "" {
+			if w.Registries, err = worker.LoadRegistries(registryConfig); err != nil {
+				return err
+			}
+		}
+
+		api := &worker.Api{Address: host, Port: port, Worker: w}
+		slog.Info("Starting worker", logging.Worker, name)
+
+		return serve(cmd.Context(), api,
+			w.RunTasks,
+			func(ctx context.Context) { w.UpdateTasks(ctx, updateInterval) },
+			func(ctx context.Context) { w.MonitorRuntime(ctx, monitorInterval) },
+		)
+	},
+}
+
+// storeType maps the value of a --dbtype flag to a store.Type.
+func storeType(name string) (store.Type, error) {
+	switch name {
+	case "memory":
+		return store.InMemoryStoreType, nil
+	default:
+		return 0, fmt.Errorf("unsupported datastore type %q", name)
+	}
 }
@@ -0,0 +1,165 @@
+package cronjob
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// ConcurrencyPolicy determines what happens when a run is due while tasks of
+// earlier runs are still active.
+type ConcurrencyPolicy string
+
+const (
+	// Allow starts the new run alongside the active ones. This is the default.
+	Allow ConcurrencyPolicy = "Allow"
+	// Forbid skips the new run.
+	Forbid ConcurrencyPolicy = "Forbid"
+	// Replace stops the active tasks and starts the new run.
+	Replace ConcurrencyPolicy = "Replace"
+)
+
+const (
+	// DefaultHistoryLimit is the number of finished tasks kept when HistoryLimit is zero.
+	DefaultHistoryLimit = 3
+	// DefaultCatchUpLimit is the number of due runs started at once when CatchUpLimit is zero.
+	DefaultCatchUpLimit = 1
+)
+
+// CronJob runs a task from a template on a cron schedule.
+type CronJob struct {
+	ID   uuid.UUID
+	Name string
+	// Schedule is a standard five-field cron expression or a descriptor such as "@hourly".
+	Schedule          string
+	Template          task.Task
+	ConcurrencyPolicy ConcurrencyPolicy `json:",omitempty"`
+	// CatchUpLimit is the maximum number of due runs started in a single evaluation,
+	// e.g. after the manager was down. Older missed runs beyond it are skipped.
+	CatchUpLimit int `json:",omitempty"`
+	// HistoryLimit is the number of finished tasks that are kept.
+	HistoryLimit     int `json:",omitempty"`
+	LastScheduleTime time.Time
+	// Active holds the tasks of runs that have not finished yet.
+	Active []uuid.UUID
+	// History holds the finished tasks, oldest first.
+	History   []uuid.UUID
+	CreatedAt time.Time
+}
+
+// New creates a cron job with a new ID after validating its schedule and policy.
+//
+// Parameters:
+//   - cj: The cron job as submitted
+//
+// Returns:
+//   - *CronJob: The cron job, ready to be evaluated
+//   - error: If the schedule or concurrency policy is invalid
+func New(cj CronJob) (*CronJob, error) {
+	if cj.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if _, err := cj.schedule(); err != nil {
+		return nil, err
+	}
+	switch cj.ConcurrencyPolicy {
+	case "", Allow, Forbid, Replace:
+	default:
+		return nil, fmt.Errorf("invalid concurrency policy %q", cj.ConcurrencyPolicy)
+	}
+	if cj.CatchUpLimit < 0 || cj.HistoryLimit < 0 {
+		return nil, errors.New("limits must not be negative")
+	}
+	if fields := cj.Template.ValidateTemplate("Template."); len(fields) > 0 {
+		return nil, &task.ValidationError{Fields: fields}
+	}
+
+	cj.ID = uuid.New()
+	cj.CreatedAt = time.Now().UTC()
+	cj.LastScheduleTime = time.Time{}
+	cj.Active = nil
+	cj.History = nil
+	return &cj, nil
+}
+
+// DueRuns returns the scheduled times in (LastScheduleTime, now], or since the
+// cron job was created if it never ran, limited to the most recent CatchUpLimit.
+//
+// Parameters:
+//   - now: The current time
+//
+// Returns:
+//   - []time.Time: The runs to start, oldest first
+//   - int: The number of missed runs that were skipped because of CatchUpLimit
+func (cj *CronJob) DueRuns(now time.Time) ([]time.Time, int) {
+	sched, err := cj.schedule()
+	if err != nil {
+		return nil, 0
+	}
+
+	from := cj.LastScheduleTime
+	if from.IsZero() {
+		from = cj.CreatedAt
+	}
+
+	var due []time.Time
+	for next := sched.Next(from); !next.After(now); next = sched.Next(next) {
+		due = append(due, next)
+	}
+
+	limit := cj.CatchUpLimit
+	if limit == 0 {
+		limit = DefaultCatchUpLimit
+	}
+	if len(due) > limit {
+		skipped := len(due) - limit
+		return due[skipped:], skipped
+	}
+	return due, 0
+}
+
+// NewTask returns the task for a run. Runs always complete, so the task is a job
+// regardless of the kind in the template.
+func (cj *CronJob) NewTask() task.Task {
+	t := cj.Template
+	t.ID = uuid.New()
+	t.Kind = task.KindJob
+	t.State = task.Pending
+	t.Inputs = nil
+	return t
+}
+
+// Finish moves a task from the active runs to the history.
+//
+// Returns:
+//   - []uuid.UUID: The oldest finished tasks that no longer fit in the history
+func (cj *CronJob) Finish(id uuid.UUID) []uuid.UUID {
+	cj.Active = slices.DeleteFunc(cj.Active, func(a uuid.UUID) bool { return a == id })
+	cj.History = append(cj.History, id)
+
+	limit := cj.HistoryLimit
+	if limit == 0 {
+		limit = DefaultHistoryLimit
+	}
+	if len(cj.History) <= limit {
+		return nil
+	}
+
+	n := len(cj.History) - limit
+	evicted := slices.Clone(cj.History[:n])
+	cj.History = slices.Delete(cj.History, 0, n)
+	return evicted
+}
+
+func (cj *CronJob) schedule() (cron.Schedule, error) {
+	sched, err := cron.ParseStandard(cj.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", cj.Schedule, err)
+	}
+	return sched, nil
+}
@@ -1 +1,99 @@
 package flow
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// Step is a node of a workflow: a task spec together with the names of the steps
+// whose tasks must complete before it is dispatched.
+type Step struct {
+	Name      string
+	Task      task.Task
+	DependsOn []string `json:",omitempty"`
+	State     State
+	// TaskID is the ID of the task dispatched for the step; it is zero until then.
+	TaskID uuid.UUID
+}
+
+// InputsDir is the directory under which the artifacts of upstream steps are
+// mounted, as InputsDir/<step>/<artifact>.
+const InputsDir = task.InputsDir
+
+// Upstream is a completed step that a step depends on, with the task that ran it
+// and the worker holding its artifacts.
+type Upstream struct {
+	Step   string
+	Task   *task.Task
+	Worker string
+}
+
+// NewTask returns the task to dispatch for the step. Steps always run to
+// completion, so the task is a job regardless of the kind in the spec.
+//
+// The outputs of each upstream step are passed as environment variables named
+// <STEP>_<KEY>, and its artifacts are mounted read-only under InputsDir.
+//
+// Parameters:
+//   - upstream: The completed steps the step depends on
+func (s *Step) NewTask(upstream []Upstream) task.Task {
+	t := s.Task
+	t.ID = uuid.New()
+	t.Kind = task.KindJob
+	t.State = task.Pending
+	t.Env = slices.Clone(t.Env)
+	t.Inputs = nil
+
+	for _, u := range upstream {
+		for key, value := range u.Task.Outputs {
+			t.Env = append(t.Env, fmt.Sprintf("%s_%s=%s", envName(u.Step), envName(key), value))
+		}
+		for _, a := range u.Task.Artifacts {
+			t.Inputs = append(t.Inputs, task.ArtifactInput{
+				TaskID:    u.Task.ID,
+				Name:      a.Name,
+				Worker:    u.Worker,
+				MountPath: path.Join(InputsDir, u.Step, a.Name),
+			})
+		}
+	}
+	return t
+}
+
+// Observe updates the state of a dispatched step from the state of its task. A
+// failed task that will still be retried keeps the step running.
+//
+// Parameters:
+//   - t: The task dispatched for the step
+func (s *Step) Observe(t *task.Task) {
+	switch {
+	case t.State == task.Completed:
+		s.State = Completed
+	case t.State == task.Failed && !t.CanRetry():
+		s.State = Failed
+	default:
+		s.State = Running
+	}
+}
+
+// envName turns a step name or output key into an environment variable name,
+// e.g. "extract-data" into "EXTRACT_DATA".
+func envName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, s)
+}
+
+// isDispatched reports whether a task has been created for the step.
+func (s *Step) isDispatched() bool {
+	return s.TaskID != uuid.Nil
+}
@@ -0,0 +1,224 @@
+package flow
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/handler"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// State is the state of a workflow or of one of its steps.
+type State uint
+
+const (
+	Pending State = iota
+	Running
+	Completed
+	Failed
+	// Skipped is only used for steps whose upstream steps failed or were skipped.
+	Skipped
+)
+
+// Workflow is a DAG of steps. Each step is dispatched as a task once all the
+// steps it depends on have completed.
+type Workflow struct {
+	ID         uuid.UUID
+	Name       string
+	Steps      []*Step
+	State      State
+	CreatedAt  time.Time
+	FinishedAt time.Time
+}
+
+// New creates a pending workflow from the given steps.
+//
+// Parameters:
+//   - name: The name of the workflow
+//   - steps: The steps of the workflow
+//
+// Returns:
+//   - *Workflow: The workflow with a new ID and every step pending
+//   - error: If the steps do not form a valid DAG
+func New(name string, steps []*Step) (*Workflow, error) {
+	wf := &Workflow{
+		ID:        uuid.New(),
+		Name:      name,
+		Steps:     steps,
+		State:     Pending,
+		CreatedAt: time.Now().UTC(),
+	}
+	for _, s := range steps {
+		s.State = Pending
+		s.TaskID = uuid.Nil
+	}
+
+	if err := wf.Validate(); err != nil {
+		return nil, err
+	}
+	return wf, nil
+}
+
+// Validate checks that the workflow has at least one step, that step names are
+// unique, that every dependency refers to another step, that there are no cycles
+// and that the task of every step is valid.
+func (wf *Workflow) Validate() error {
+	if len(wf.Steps) == 0 {
+		return errors.New("workflow has no steps")
+	}
+
+	steps := make(map[string]*Step, len(wf.Steps))
+	for _, s := range wf.Steps {
+		if s.Name == "" {
+			return errors.New("step name is required")
+		}
+		if _, ok := steps[s.Name]; ok {
+			return fmt.Errorf("duplicate step %q", s.Name)
+		}
+		steps[s.Name] = s
+	}
+
+	for _, s := range wf.Steps {
+		for _, dep := range s.DependsOn {
+			if dep == s.Name {
+				return fmt.Errorf("step %q depends on itself", s.Name)
+			}
+			if _, ok := steps[dep]; !ok {
+				return fmt.Errorf("step %q depends on unknown step %q", s.Name, dep)
+			}
+		}
+	}
+
+	if len(wf.order()) != len(wf.Steps) {
+		return errors.New("workflow steps contain a dependency cycle")
+	}
+
+	var fields []handler.FieldError
+	for i, s := range wf.Steps {
+		fields = append(fields, s.Task.ValidateTemplate(fmt.Sprintf("Steps[%d].Task.", i))...)
+	}
+	if len(fields) > 0 {
+		return &task.ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// Step returns the step with the given name, or nil if there is none.
+func (wf *Workflow) Step(name string) *Step {
+	for _, s := range wf.Steps {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Ready returns the pending steps whose dependencies have all completed.
+func (wf *Workflow) Ready() []*Step {
+	var ready []*Step
+	for _, s := range wf.Steps {
+		if s.State != Pending || s.isDispatched() {
+			continue
+		}
+		if wf.allDeps(s, Completed) {
+			ready = append(ready, s)
+		}
+	}
+	return ready
+}
+
+// Propagate marks every pending step with a failed or skipped upstream step as skipped.
+func (wf *Workflow) Propagate() {
+	for _, s := range wf.order() {
+		if s.State != Pending {
+			continue
+		}
+		for _, dep := range s.DependsOn {
+			if st := wf.Step(dep).State; st == Failed || st == Skipped {
+				s.State = Skipped
+				break
+			}
+		}
+	}
+}
+
+// UpdateState derives the state of the workflow from the states of its steps.
+// The workflow is running while any step is running or can still be dispatched,
+// failed once nothing is left to run and any step failed, and completed otherwise.
+func (wf *Workflow) UpdateState() {
+	var running, pending, failed bool
+	for _, s := range wf.Steps {
+		switch s.State {
+		case Running:
+			running = true
+		case Pending:
+			pending = true
+		case Failed:
+			failed = true
+		}
+	}
+
+	switch {
+	case running || pending:
+		if wf.State == Pending && running {
+			wf.State = Running
+		}
+		return
+	case failed:
+		wf.State = Failed
+	default:
+		wf.State = Completed
+	}
+	wf.FinishedAt = time.Now().UTC()
+}
+
+// IsFinished reports whether the workflow has completed or failed.
+func (wf *Workflow) IsFinished() bool {
+	return wf.State == Completed || wf.State == Failed
+}
+
+// allDeps reports whether all the dependencies of s are in state st.
+func (wf *Workflow) allDeps(s *Step, st State) bool {
+	for _, dep := range s.DependsOn {
+		if wf.Step(dep).State != st {
+			return false
+		}
+	}
+	return true
+}
+
+// order returns the steps in topological order. Steps that are part of a cycle
+// are left out.
+func (wf *Workflow) order() []*Step {
+	inDegree := make(map[string]int, len(wf.Steps))
+	dependents := make(map[string][]*Step, len(wf.Steps))
+	for _, s := range wf.Steps {
+		inDegree[s.Name] = len(s.DependsOn)
+		for _, dep := range s.DependsOn {
+			dependents[dep] = append(dependents[dep], s)
+		}
+	}
+
+	var queue, ordered []*Step
+	for _, s := range wf.Steps {
+		if inDegree[s.Name] == 0 {
+			queue = append(queue, s)
+		}
+	}
+
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		ordered = append(ordered, s)
+
+		for _, d := range dependents[s.Name] {
+			inDegree[d.Name]--
+			if inDegree[d.Name] == 0 {
+				queue = append(queue, d)
+			}
+		}
+	}
+	return ordered
+}
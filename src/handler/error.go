@@ -2,8 +2,9 @@ package handler
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
+	"strings"
 )
 
 type ResponseError struct {
@@ -11,6 +12,16 @@ type ResponseError struct {
 	Message    string `json:"message"`
 	Reason     string `json:"reason"`
 	Details    string `json:"details"`
+	// Fields lists the invalid fields of a rejected request.
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError describes why a field of a request is invalid.
+type FieldError struct {
+	// Field is the path of the field, e.g. "spec.ports[0]", or the name of a
+	// query parameter.
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 // Err creates a new ResponseError with the given status code, message and error details
@@ -35,8 +46,21 @@ func Err(code int, message string, err error) ResponseError {
 	}
 }
 
+// ValidationErr creates a 400 Bad Request ResponseError listing the invalid
+// fields of a request.
+func ValidationErr(message string, fields []FieldError) ResponseError {
+	e := Err(http.StatusBadRequest, message, nil)
+	e.Fields = fields
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	e.Details = strings.Join(parts, "; ")
+	return e
+}
+
 func SendErr(w http.ResponseWriter, e ResponseError) {
 	w.WriteHeader(e.StatusCode)
 	_ = json.NewEncoder(w).Encode(e)
-	log.Printf("Error sent to client: %v\n", e)
+	slog.Warn("Error sent to client", "status", e.StatusCode, "message", e.Message, "details", e.Details)
 }
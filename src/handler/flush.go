@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+)
+
+// flushWriter flushes every write to the client.
+type flushWriter struct {
+	w http.ResponseWriter
+	f http.Flusher
+}
+
+// FlushWriter returns a writer that sends each write to the client immediately,
+// for responses that are streamed as they are produced. If w cannot be flushed
+// it is returned unchanged.
+func FlushWriter(w http.ResponseWriter) io.Writer {
+	f, ok := w.(http.Flusher)
+	if !ok {
+		return w
+	}
+	return flushWriter{w: w, f: f}
+}
+
+func (fw flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	fw.f.Flush()
+	return n, err
+}
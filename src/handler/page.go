@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// NextCursorHeader carries the cursor of the next page of a list response;
+	// it is absent on the last page.
+	NextCursorHeader = "X-Next-Cursor"
+	// TotalCountHeader carries the number of items matching a list request
+	// across all pages.
+	TotalCountHeader = "X-Total-Count"
+)
+
+// SendPage writes one page of a list as a JSON array with 200 OK, and the
+// cursor of the next page and the total count as headers.
+func SendPage(w http.ResponseWriter, items any, next string, total int) error {
+	w.Header().Set("Content-Type", "application/json")
+	if next != "" {
+		w.Header().Set(NextCursorHeader, next)
+	}
+	w.Header().Set(TotalCountHeader, strconv.Itoa(total))
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(items)
+}
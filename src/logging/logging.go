@@ -0,0 +1,96 @@
+// Package logging configures the structured logger used by the manager, the
+// worker and the task package, and defines the names of the fields they attach.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Field names shared by all log records.
+const (
+	TaskID      = "task_id"
+	EventID     = "event_id"
+	Worker      = "worker"
+	ContainerID = "container_id"
+	Image       = "image"
+	Service     = "service"
+	Workflow    = "workflow"
+	CronJob     = "cronjob"
+	Webhook     = "webhook"
+)
+
+// Format selects how log records are encoded.
+type Format string
+
+const (
+	// FormatText writes records as key=value pairs. This is the default.
+	FormatText Format = "text"
+	// FormatJSON writes one JSON object per record.
+	FormatJSON Format = "json"
+)
+
+// Setup makes a logger writing to w the default slog logger. Output of the
+// standard log package is redirected to it at the info level.
+//
+// Parameters:
+//   - w: Where records are written
+//   - level: The minimum level, one of "debug", "info", "warn" or "error"
+//   - format: The encoding of the records
+//
+// Returns:
+//   - error: If the level or format is unknown
+func Setup(w io.Writer, level string, format Format) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(strings.ToUpper(level))); err != nil {
+		return fmt.Errorf("invalid log level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var h slog.Handler
+	switch format {
+	case "", FormatText:
+		h = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("invalid log format %q", format)
+	}
+
+	slog.SetDefault(slog.New(h))
+	return nil
+}
+
+// Middleware logs every HTTP request with its method, path, status and duration.
+// Metrics scrapes are logged at the debug level.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		start := time.Now()
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		level := slog.LevelInfo
+		if r.URL.Path == "/metrics" {
+			level = slog.LevelDebug
+		}
+		slog.Log(r.Context(), level, "Handled request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", status,
+			"bytes", ww.BytesWritten(),
+			"duration", time.Since(start),
+			"remote", r.RemoteAddr,
+		)
+	})
+}
@@ -1,11 +1,9 @@
 package main
 
 import (
-	"fmt"
 	"github.com/utkarsh5026/Orchestra/cmd"
 )
 
 func main() {
-	fmt.Println("Starting the Orch CLI")
 	cmd.Start()
 }
@@ -0,0 +1,169 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/handler"
+	"github.com/utkarsh5026/Orchestra/store"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// ErrInvalidObject is wrapped by the errors returned when a service, cron job or
+// workflow is invalid.
+var ErrInvalidObject = errors.New("invalid object")
+
+// SubmitTask admits a task event submitted through the API and adds it to the
+// pending queue.
+//
+// The event and task are assigned IDs if they have none and the inputs of the
+// task are cleared. The task is then
+// passed to the mutating webhooks, checked with task.Task.Validate and passed
+// to the validating webhooks. The event is rejected if either ID is already in
+// use, if it does not schedule a new task, if the task is invalid or if a
+// webhook rejects it.
+//
+// Parameters:
+//   - ctx: Context of the submission, bounding the calls of the webhooks
+//   - te: The submitted event; a missing State means Scheduled
+//
+// Returns:
+//   - task.Event: The queued event, with its IDs and timestamp assigned and the
+//     changes of the mutating webhooks applied
+//   - error: A *task.ValidationError listing the invalid fields, named after the
+//     fields of task.Event, e.g. "Task.Image", or an
+//     *AdmissionError if a webhook rejected the task; nothing is queued then
+func (m *Manager) SubmitTask(ctx context.Context, te task.Event) (task.Event, error) {
+	var errs []handler.FieldError
+	add := func(field, format string, args ...any) {
+		errs = append(errs, handler.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if te.ID == uuid.Nil {
+		te.ID = uuid.New()
+	} else if _, err := m.EventStore.Get(te.ID.String()); !errors.Is(err, store.ErrNotFound) {
+		add("ID", "event %s already exists", te.ID)
+	}
+	if te.Task.ID == uuid.Nil {
+		te.Task.ID = uuid.New()
+	} else if m.hasTask(te.Task.ID) {
+		add("Task.ID", "task %s already exists", te.Task.ID)
+	}
+	switch te.State {
+	case task.Pending, task.Scheduled:
+		te.State = task.Scheduled
+	default:
+		add("State", "must be Scheduled, got %s; stop tasks with DELETE /tasks/{taskID}", te.State)
+	}
+	if te.Timestamp.IsZero() {
+		te.Timestamp = time.Now().UTC()
+	}
+	if te.RegistryAuth != nil {
+		add("RegistryAuth", "must not be set; reference a registry secret with Task.ImagePullSecret")
+	}
+	// Inputs point the worker at artifacts of other tasks; only the manager
+	// sets them, for the steps of a workflow.
+	te.Task.Inputs = nil
+	if len(errs) > 0 {
+		return te, &task.ValidationError{Fields: errs}
+	}
+
+	fields, err := m.admit(ctx, &te, "")
+	if err != nil {
+		return te, err
+	}
+	for _, f := range fields {
+		errs = append(errs, handler.FieldError{Field: "Task." + f.Field, Message: f.Message})
+	}
+	if len(errs) > 0 {
+		return te, &task.ValidationError{Fields: errs}
+	}
+
+	// The webhooks are called without holding pendingMu, so check again that
+	// the same task was not submitted in the meantime.
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+	if m.taskExists(te.Task.ID) {
+		add("Task.ID", "task %s already exists", te.Task.ID)
+		return te, &task.ValidationError{Fields: errs}
+	}
+	m.enqueue(te)
+	return te, nil
+}
+
+// AdmitTemplate passes a task template, such as the template of a service, the
+// template of a cron job or the task of a workflow step, through the admission
+// chain of the tasks submitted through the API, so that the tasks the manager
+// later creates from it are held to the same policy.
+//
+// Parameters:
+//   - ctx: Context bounding the calls of the webhooks
+//   - t: The template; it is replaced by the template the mutating webhooks return
+//   - object: The object the template belongs to, e.g. "Service/web", which is
+//     sent to the webhooks
+//   - prefix: Prepended to the names of the invalid fields, e.g. "Template."
+//
+// Returns:
+//   - error: A *task.ValidationError listing the invalid fields of the mutated
+//     template, or an *AdmissionError if a webhook rejected it; t is unchanged then
+func (m *Manager) AdmitTemplate(ctx context.Context, t *task.Task, object, prefix string) error {
+	te := task.Event{ID: uuid.New(), State: task.Scheduled, Timestamp: time.Now().UTC(), Task: *t}
+	te.Task.ID = uuid.Nil
+	te.Task.State = task.Pending
+	te.Task.Inputs = nil
+
+	fields, err := m.admit(ctx, &te, object)
+	if err != nil {
+		return err
+	}
+	if len(fields) > 0 {
+		for i := range fields {
+			fields[i].Field = prefix + fields[i].Field
+		}
+		return &task.ValidationError{Fields: fields}
+	}
+	*t = te.Task
+	return nil
+}
+
+// admit passes the task of an event to the mutating webhooks, checks it with
+// task.Task.Validate and passes it to the validating webhooks.
+//
+// Parameters:
+//   - ctx: Context bounding the calls of the webhooks
+//   - te: The event; its task is replaced by the one the mutating webhooks return
+//   - object: The object whose template the task is, or empty for a task
+//
+// Returns:
+//   - []handler.FieldError: The invalid fields of the mutated task, named after
+//     the fields of task.Task; the validating webhooks are not called then
+//   - error: An *AdmissionError if a webhook rejected the task
+func (m *Manager) admit(ctx context.Context, te *task.Event, object string) ([]handler.FieldError, error) {
+	if err := m.callMutatingWebhooks(ctx, te, object); err != nil {
+		return nil, err
+	}
+	if fields := te.Task.Validate(); len(fields) > 0 {
+		return fields, nil
+	}
+	return nil, m.callValidatingWebhooks(ctx, te, object)
+}
+
+// hasTask reports whether a task with the given ID is stored or pending.
+func (m *Manager) hasTask(id uuid.UUID) bool {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+	return m.taskExists(id)
+}
+
+// taskExists reports whether a task with the given ID is stored or pending.
+// The caller must hold pendingMu.
+func (m *Manager) taskExists(id uuid.UUID) bool {
+	if _, ok := m.pendingTasks[id]; ok {
+		return true
+	}
+	_, err := m.TaskStore.Get(id.String())
+	return !errors.Is(err, store.ErrNotFound)
+}
@@ -1,12 +1,19 @@
 package manager
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"sync"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/utkarsh5026/Orchestra/logging"
+	"github.com/utkarsh5026/Orchestra/metrics"
+	"github.com/utkarsh5026/Orchestra/tracing"
 )
 
 type Api struct {
@@ -14,23 +21,108 @@ type Api struct {
 	Port    uint
 	Manager *Manager
 	Router  *chi.Mux
+
+	mu     sync.Mutex
+	server *http.Server
 }
 
 func (a *Api) initRouter() {
 	a.Router = chi.NewRouter()
-	a.Router.Use(middleware.Logger)
+	a.Router.Use(logging.Middleware)
 	a.Router.Use(middleware.Recoverer)
+	a.Router.Use(tracing.Middleware("manager"))
+
+	a.Router.Handle("/metrics", metrics.Handler())
+	a.Router.Get("/openapi.json", a.OpenAPIHandler)
+	a.Router.Route("/v1", a.v1Routes)
+
+	// The unversioned routes below predate /v1 and exchange internal types.
 
 	a.Router.Route("/tasks", func(r chi.Router) {
 		r.Post("/", a.StartTaskHandler)
 		r.Get("/", a.GetTasksHandler)
+		r.Get("/stats", a.GetStatsHandler)
+		r.Get("/watch", a.WatchTasksHandler)
+		r.Get("/{taskID}", a.GetTaskHandler)
 		r.Delete("/{taskID}", a.StopTaskHandler)
+		r.Get("/{taskID}/stats", a.GetTaskStatsHandler)
+		r.Get("/{taskID}/events", a.GetTaskEventsHandler)
+		r.Get("/{taskID}/logs", a.GetTaskLogsHandler)
 	})
+
+	a.Router.Get("/nodes", a.GetNodesHandler)
+	a.Router.Post("/apply", a.ApplyHandler)
+
+	a.Router.Route("/workflows", func(r chi.Router) {
+		r.Post("/", a.SubmitWorkflowHandler)
+		r.Get("/", a.GetWorkflowsHandler)
+		r.Get("/{workflowID}", a.GetWorkflowHandler)
+		r.Delete("/{workflowID}", a.DeleteWorkflowHandler)
+	})
+
+	a.Router.Route("/cronjobs", func(r chi.Router) {
+		r.Post("/", a.CreateCronJobHandler)
+		r.Get("/", a.GetCronJobsHandler)
+		r.Get("/{cronJobID}", a.GetCronJobHandler)
+		r.Delete("/{cronJobID}", a.DeleteCronJobHandler)
+	})
+
+	a.Router.Route("/services", func(r chi.Router) {
+		r.Post("/", a.CreateServiceHandler)
+		r.Get("/", a.GetServicesHandler)
+		r.Get("/{serviceID}", a.GetServiceHandler)
+		r.Put("/{serviceID}", a.UpdateServiceHandler)
+		r.Put("/{serviceID}/replicas", a.ScaleServiceHandler)
+		r.Post("/{serviceID}/rollback", a.RollbackServiceHandler)
+		r.Post("/{serviceID}/resume", a.ResumeServiceHandler)
+		r.Delete("/{serviceID}", a.DeleteServiceHandler)
+	})
+
+	a.Router.Put("/secrets/{name}", a.PutSecretHandler)
 }
 
-func (a *Api) Start() {
+// Start serves the API on Address:Port until Shutdown is called.
+//
+// Returns:
+//   - error: If the server cannot listen or fails; nil after Shutdown
+func (a *Api) Start() error {
 	a.initRouter()
 
-	log.Printf("Starting API server on %s:%d", a.Address, a.Port)
-	http.ListenAndServe(fmt.Sprintf("%s:%d", a.Address, a.Port), a.Router)
+	if err := metrics.RegisterNode(fmt.Sprintf("%s:%d", a.Address, a.Port)); err != nil {
+		slog.Error("Error registering node metrics", "error", err)
+	}
+
+	// Requests are cancelled when the server shuts down, which ends the
+	// long-lived task watches that Shutdown would otherwise wait for.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a.mu.Lock()
+	a.server = &http.Server{
+		Addr:        fmt.Sprintf("%s:%d", a.Address, a.Port),
+		Handler:     a.Router,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	a.server.RegisterOnShutdown(cancel)
+	srv := a.server
+	a.mu.Unlock()
+
+	slog.Info("Starting manager API server", "address", a.Address, "port", a.Port)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("manager API server failed: %w", err)
+	}
+	return nil
+}
+
+// Shutdown stops the server from accepting new requests and waits for the
+// in-flight ones to finish until ctx expires.
+func (a *Api) Shutdown(ctx context.Context) error {
+	a.mu.Lock()
+	srv := a.server
+	a.mu.Unlock()
+
+	if srv == nil {
+		return nil
+	}
+	return srv.Shutdown(ctx)
 }
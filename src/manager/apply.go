@@ -0,0 +1,485 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/cronjob"
+	"github.com/utkarsh5026/Orchestra/flow"
+	"github.com/utkarsh5026/Orchestra/manifest"
+	"github.com/utkarsh5026/Orchestra/service"
+	"github.com/utkarsh5026/Orchestra/store"
+	"github.com/utkarsh5026/Orchestra/task"
+	"github.com/utkarsh5026/Orchestra/utils"
+)
+
+// AppliedObject records an object that is managed through manifests.
+type AppliedObject struct {
+	Kind manifest.Kind
+	Name string
+	// ID is the ID of the task, service, cron job or workflow.
+	ID uuid.UUID
+	// Spec is the spec that was last applied, with its task templates admitted.
+	Spec json.RawMessage
+}
+
+// ApplyRequest is the body of a request to apply manifests.
+type ApplyRequest struct {
+	Objects []manifest.Object
+	// Prune deletes the objects created from earlier manifests that are no longer declared.
+	Prune bool `json:",omitempty"`
+	// DryRun only computes the changes without making them.
+	DryRun bool `json:",omitempty"`
+}
+
+// ErrInvalidManifest is wrapped by errors returned by Apply for objects that
+// cannot be decoded or validated; nothing is changed in that case.
+var ErrInvalidManifest = errors.New("invalid manifest")
+
+// plannedChange is a change together with what is needed to make it.
+type plannedChange struct {
+	manifest.Change
+	object manifest.Object
+	// id is the ID of the existing object, if there is one.
+	id uuid.UUID
+	// spec is the spec of object with its task templates admitted.
+	spec json.RawMessage
+}
+
+// Apply converges the tasks, services, cron jobs and workflows declared by
+// objs. Objects are matched by kind and name: missing ones are created, ones
+// whose spec differs are updated and, with req.Prune, ones created from earlier
+// manifests that are no longer declared are deleted.
+//
+// Services and cron jobs are updated in place; a changed service template is
+// rolled out as a new revision. Tasks and workflows cannot change once they
+// run, so they are replaced: the old one is stopped and a new one is started.
+//
+// The task templates of the declared objects pass through the admission chain
+// of SubmitTask when the changes are planned, so that a rejected object stops
+// the apply before anything is changed, and again when they are created or updated.
+//
+// Parameters:
+//   - ctx: Context bounding the calls of the admission webhooks
+//   - req: The declared objects and how to apply them
+//
+// Returns:
+//   - []manifest.Change: The changes, made unless req.DryRun is set
+//   - error: Wrapping ErrInvalidManifest if an object is invalid, wrapping an
+//     *AdmissionError if a webhook rejected one, or the error that stopped the
+//     changes; the changes made until then are returned with it
+func (m *Manager) Apply(ctx context.Context, req ApplyRequest) ([]manifest.Change, error) {
+	m.applyMu.Lock()
+	defer m.applyMu.Unlock()
+
+	plan, err := m.planApply(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]manifest.Change, 0, len(plan))
+	for _, p := range plan {
+		if !req.DryRun && p.Action != manifest.ActionUnchanged {
+			if err := m.applyChange(ctx, p); err != nil {
+				return changes, fmt.Errorf("failed to %s %s: %w", p.Action, p.Key(), err)
+			}
+			slog.Info("Applied manifest change", "object", p.Key(), "action", p.Action)
+		}
+		changes = append(changes, p.Change)
+	}
+	return changes, nil
+}
+
+// planApply validates the declared objects and compares each one with the
+// object it manages, followed by the deletions if req.Prune is set.
+func (m *Manager) planApply(ctx context.Context, req ApplyRequest) ([]plannedChange, error) {
+	if err := manifest.Validate(req.Objects); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+	}
+
+	declared := make(map[string]bool, len(req.Objects))
+	var plan []plannedChange
+	for _, o := range req.Objects {
+		declared[o.Key()] = true
+		p, err := m.planObject(ctx, o)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+		}
+		plan = append(plan, p)
+	}
+
+	if !req.Prune {
+		return plan, nil
+	}
+
+	applied, err := m.Applied.List()
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(applied, func(a, b *AppliedObject) int {
+		return strings.Compare(manifest.Key(a.Kind, a.Name), manifest.Key(b.Kind, b.Name))
+	})
+	for _, a := range applied {
+		key := manifest.Key(a.Kind, a.Name)
+		if declared[key] {
+			continue
+		}
+		p := plannedChange{
+			Change: manifest.Change{Kind: a.Kind, Name: a.Name, Action: manifest.ActionDelete},
+			object: manifest.Object{Kind: a.Kind, Name: a.Name},
+			id:     a.ID,
+		}
+		plan = append(plan, p)
+	}
+	return plan, nil
+}
+
+// planObject decides how to converge the object managing o to its spec.
+func (m *Manager) planObject(ctx context.Context, o manifest.Object) (plannedChange, error) {
+	desired, err := m.desiredSpec(ctx, o)
+	if err != nil {
+		return plannedChange{}, err
+	}
+	spec, err := json.Marshal(desired)
+	if err != nil {
+		return plannedChange{}, err
+	}
+
+	p := plannedChange{
+		Change: manifest.Change{Kind: o.Kind, Name: o.Name, Action: manifest.ActionCreate},
+		object: o,
+		spec:   spec,
+	}
+	id, current, ok := m.currentSpec(o)
+	if !ok {
+		return p, nil
+	}
+	p.id = id
+
+	if o.Kind == manifest.KindService {
+		svcSpec := desired.(manifest.ServiceSpec)
+		if svcSpec.Autoscaling != nil {
+			// The replica count of an autoscaled service is managed by the autoscaler.
+			svcSpec.Replicas = current.(manifest.ServiceSpec).Replicas
+			desired = svcSpec
+		}
+	}
+
+	p.Fields, err = manifest.Diff(current, desired)
+	if err != nil {
+		return plannedChange{}, err
+	}
+	switch {
+	case len(p.Fields) == 0:
+		p.Action = manifest.ActionUnchanged
+	case o.Kind == manifest.KindTask || o.Kind == manifest.KindWorkflow:
+		p.Action = manifest.ActionReplace
+	default:
+		p.Action = manifest.ActionUpdate
+	}
+	return p, nil
+}
+
+// desiredSpec decodes and validates the spec of o, admits its task templates
+// and returns it in the form used to compare it with existing objects. Since
+// the templates are compared once admitted, the changes the mutating webhooks
+// make are not reported as differences.
+func (m *Manager) desiredSpec(ctx context.Context, o manifest.Object) (any, error) {
+	switch o.Kind {
+	case manifest.KindTask:
+		t, err := o.Task()
+		if err != nil {
+			return nil, err
+		}
+		if err := m.admitTemplate(ctx, o, &t, ""); err != nil {
+			return nil, err
+		}
+		return manifest.TaskSpec(t), nil
+	case manifest.KindService:
+		svc, err := o.Service()
+		if err != nil {
+			return nil, err
+		}
+		if err := m.admitTemplate(ctx, o, &svc.Template, "Template."); err != nil {
+			return nil, err
+		}
+		return manifest.ServiceSpecOf(svc), nil
+	case manifest.KindCronJob:
+		cj, err := o.CronJob()
+		if err != nil {
+			return nil, err
+		}
+		if err := m.admitTemplate(ctx, o, &cj.Template, "Template."); err != nil {
+			return nil, err
+		}
+		return manifest.CronJobSpecOf(cj), nil
+	case manifest.KindWorkflow:
+		wf, err := o.Workflow()
+		if err != nil {
+			return nil, err
+		}
+		for i, s := range wf.Steps {
+			if err := m.admitTemplate(ctx, o, &s.Task, fmt.Sprintf("Steps[%d].Task.", i)); err != nil {
+				return nil, err
+			}
+		}
+		return manifest.WorkflowSpecOf(wf), nil
+	}
+	return nil, fmt.Errorf("unknown kind %q", o.Kind)
+}
+
+// admitTemplate passes a task template of o through AdmitTemplate.
+func (m *Manager) admitTemplate(ctx context.Context, o manifest.Object, t *task.Task, prefix string) error {
+	if err := m.AdmitTemplate(ctx, t, o.Key(), prefix); err != nil {
+		return fmt.Errorf("%s: %w", o.Key(), err)
+	}
+	return nil
+}
+
+// currentSpec returns the ID and spec of the existing object that o manages:
+// the one recorded when o was last applied or, for an object created through
+// the API, the first one of the same kind and name.
+//
+// Returns:
+//   - uuid.UUID: The ID of the existing object
+//   - any: Its spec, of the same type as desiredSpec returns
+//   - bool: false if there is no such object
+func (m *Manager) currentSpec(o manifest.Object) (uuid.UUID, any, bool) {
+	var recorded *AppliedObject
+	if a, err := m.Applied.Get(o.Key()); err == nil {
+		recorded = a
+	}
+
+	switch o.Kind {
+	case manifest.KindTask:
+		if recorded != nil {
+			if t, err := m.TaskStore.Get(recorded.ID.String()); err == nil {
+				return t.ID, manifest.TaskSpec(*t), true
+			}
+			// The task has not been scheduled on a worker yet, or it finished
+			// and was cleaned up; either way it must not be started again.
+			applied := manifest.Object{Kind: recorded.Kind, Name: recorded.Name, Spec: recorded.Spec}
+			if t, err := applied.Task(); err == nil {
+				return recorded.ID, t, true
+			}
+		}
+		t, ok := findByName(m.TaskStore, o.Name, func(t *task.Task) string { return t.Name })
+		if !ok {
+			return uuid.Nil, nil, false
+		}
+		return t.ID, manifest.TaskSpec(*t), true
+	case manifest.KindService:
+		m.servicesMu.RLock()
+		defer m.servicesMu.RUnlock()
+		svc, ok := findApplied(m.Services, recorded, o.Name, func(s *service.Service) string { return s.Name })
+		if !ok {
+			return uuid.Nil, nil, false
+		}
+		return svc.ID, manifest.ServiceSpecOf(svc), true
+	case manifest.KindCronJob:
+		m.cronJobsMu.RLock()
+		defer m.cronJobsMu.RUnlock()
+		cj, ok := findApplied(m.CronJobs, recorded, o.Name, func(c *cronjob.CronJob) string { return c.Name })
+		if !ok {
+			return uuid.Nil, nil, false
+		}
+		return cj.ID, manifest.CronJobSpecOf(cj), true
+	case manifest.KindWorkflow:
+		m.workflowsMu.RLock()
+		defer m.workflowsMu.RUnlock()
+		wf, ok := findApplied(m.Workflows, recorded, o.Name, func(w *flow.Workflow) string { return w.Name })
+		if !ok {
+			return uuid.Nil, nil, false
+		}
+		return wf.ID, manifest.WorkflowSpecOf(wf), true
+	}
+	return uuid.Nil, nil, false
+}
+
+// findApplied returns the object recorded for a manifest, or the first one
+// named name if none is recorded or the recorded one no longer exists.
+func findApplied[V any](s store.Store[string, V], recorded *AppliedObject, name string, nameOf func(V) string) (V, bool) {
+	if recorded != nil {
+		if v, err := s.Get(recorded.ID.String()); err == nil {
+			return v, true
+		}
+	}
+	return findByName(s, name, nameOf)
+}
+
+// findByName returns the first object in s named name.
+func findByName[V any](s store.Store[string, V], name string, nameOf func(V) string) (V, bool) {
+	var zero V
+	all, err := s.List()
+	if err != nil {
+		return zero, false
+	}
+	for _, v := range all {
+		if nameOf(v) == name {
+			return v, true
+		}
+	}
+	return zero, false
+}
+
+// applyChange makes a planned change and updates the record of applied objects.
+func (m *Manager) applyChange(ctx context.Context, p plannedChange) error {
+	key := p.Key()
+	if p.Action == manifest.ActionDelete || p.Action == manifest.ActionReplace {
+		if err := m.deleteObject(p.Kind, p.id); err != nil && !errors.Is(err, store.ErrNotFound) {
+			return err
+		}
+		if p.Action == manifest.ActionDelete {
+			return m.Applied.Delete(key)
+		}
+	}
+
+	var id uuid.UUID
+	var err error
+	switch p.Action {
+	case manifest.ActionCreate, manifest.ActionReplace:
+		id, err = m.createObject(ctx, p.object)
+	case manifest.ActionUpdate:
+		id, err = p.id, m.updateObject(ctx, p.object, p.id)
+	}
+	if err != nil {
+		return err
+	}
+
+	return m.Applied.Put(key, &AppliedObject{Kind: p.Kind, Name: p.Name, ID: id, Spec: p.spec})
+}
+
+// createObject creates the object declared by o through the methods that
+// create objects submitted to the API, which admit their task templates.
+func (m *Manager) createObject(ctx context.Context, o manifest.Object) (uuid.UUID, error) {
+	switch o.Kind {
+	case manifest.KindTask:
+		t, err := o.Task()
+		if err != nil {
+			return uuid.Nil, err
+		}
+		te, err := m.SubmitTask(ctx, task.Event{Task: t})
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return te.Task.ID, nil
+	case manifest.KindService:
+		svc, err := o.Service()
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if svc, err = m.AddService(ctx, *svc); err != nil {
+			return uuid.Nil, err
+		}
+		return svc.ID, nil
+	case manifest.KindCronJob:
+		cj, err := o.CronJob()
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if cj, err = m.AddCronJob(ctx, *cj); err != nil {
+			return uuid.Nil, err
+		}
+		return cj.ID, nil
+	case manifest.KindWorkflow:
+		wf, err := o.Workflow()
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if wf, err = m.SubmitWorkflow(ctx, wf.Name, wf.Steps); err != nil {
+			return uuid.Nil, err
+		}
+		return wf.ID, nil
+	}
+	return uuid.Nil, fmt.Errorf("unknown kind %q", o.Kind)
+}
+
+// updateObject admits the template of o and updates the service or cron job
+// id in place to the spec of o.
+func (m *Manager) updateObject(ctx context.Context, o manifest.Object, id uuid.UUID) error {
+	switch o.Kind {
+	case manifest.KindService:
+		desired, err := o.Service()
+		if err != nil {
+			return err
+		}
+		if err := m.admitTemplate(ctx, o, &desired.Template, "Template."); err != nil {
+			return err
+		}
+		m.servicesMu.Lock()
+		defer m.servicesMu.Unlock()
+		svc, err := m.Services.Get(id.String())
+		if err != nil {
+			return err
+		}
+
+		svc.Strategy = desired.Strategy
+		svc.RevisionHistoryLimit = desired.RevisionHistoryLimit
+		svc.Autoscaling = desired.Autoscaling
+		if desired.Autoscaling == nil {
+			svc.Replicas = desired.Replicas
+		} else {
+			svc.Replicas = min(max(svc.Replicas, desired.Autoscaling.MinReplicas), desired.Autoscaling.MaxReplicas)
+		}
+		if diffs, _ := manifest.Diff(manifest.TaskSpec(svc.Template), manifest.TaskSpec(desired.Template)); len(diffs) > 0 {
+			if err := svc.Update(desired.Template); err != nil {
+				return err
+			}
+		}
+		m.reconcileService(svc)
+		return nil
+	case manifest.KindCronJob:
+		desired, err := o.CronJob()
+		if err != nil {
+			return err
+		}
+		if err := m.admitTemplate(ctx, o, &desired.Template, "Template."); err != nil {
+			return err
+		}
+		m.cronJobsMu.Lock()
+		defer m.cronJobsMu.Unlock()
+		cj, err := m.CronJobs.Get(id.String())
+		if err != nil {
+			return err
+		}
+
+		cj.Schedule = desired.Schedule
+		cj.Template = desired.Template
+		cj.ConcurrencyPolicy = desired.ConcurrencyPolicy
+		cj.CatchUpLimit = desired.CatchUpLimit
+		cj.HistoryLimit = desired.HistoryLimit
+		utils.UpdateStore(m.CronJobs, cj.ID.String(), cj)
+		return nil
+	}
+	return fmt.Errorf("%s objects cannot be updated in place", o.Kind)
+}
+
+// deleteObject stops and removes the object id of the given kind.
+func (m *Manager) deleteObject(kind manifest.Kind, id uuid.UUID) error {
+	switch kind {
+	case manifest.KindTask:
+		t, err := m.TaskStore.Get(id.String())
+		if err != nil {
+			// The task has not been scheduled yet; stopping it by ID takes effect
+			// once its start event has been dispatched.
+			t = &task.Task{ID: id}
+		}
+		if !t.IsFinished() {
+			m.StopTask(t)
+		}
+		return nil
+	case manifest.KindService:
+		return m.DeleteService(id)
+	case manifest.KindCronJob:
+		return m.DeleteCronJob(id)
+	case manifest.KindWorkflow:
+		return m.DeleteWorkflow(id)
+	}
+	return fmt.Errorf("unknown kind %q", kind)
+}
@@ -0,0 +1,83 @@
+package manager
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/utkarsh5026/Orchestra/logging"
+	"github.com/utkarsh5026/Orchestra/service"
+)
+
+// AutoscaleServices periodically adjusts the replica count of every service with
+// autoscaling enabled from the resource usage reported by its tasks.
+//
+// Parameters:
+//   - ctx: Context whose cancellation stops the loop
+//   - d: The duration to wait between evaluations
+//
+// This function runs until ctx is cancelled and should be started in a separate goroutine.
+func (m *Manager) AutoscaleServices(ctx context.Context, d time.Duration) {
+	for {
+		m.autoscaleServices(time.Now().UTC())
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d):
+		}
+	}
+}
+
+func (m *Manager) autoscaleServices(now time.Time) {
+	svcs, err := m.Services.List()
+	if err != nil {
+		slog.Error("Error listing services", "error", err)
+		return
+	}
+
+	for _, svc := range svcs {
+		m.servicesMu.Lock()
+		if m.serviceExists(svc) && svc.Autoscaling != nil {
+			m.autoscaleService(svc, now)
+		}
+		m.servicesMu.Unlock()
+	}
+}
+
+// autoscaleService averages the utilization of the ready tasks of a service and
+// reconciles it to the replica count recommended by its autoscaling settings.
+// Services that are rolling out or have no usage samples yet are left alone.
+// The caller must hold servicesMu.
+func (m *Manager) autoscaleService(svc *service.Service, now time.Time) {
+	if svc.Rollout.State != service.RolloutComplete {
+		return
+	}
+
+	as := svc.Autoscaling
+	var total float64
+	var n int
+	for _, r := range svc.Tasks {
+		t, err := m.TaskStore.Get(r.TaskID.String())
+		if err != nil || !t.IsReady() {
+			continue
+		}
+		if u, ok := as.Utilization(t); ok {
+			total += u
+			n++
+		}
+	}
+	if n == 0 {
+		return
+	}
+
+	average := total / float64(n)
+	replicas := as.Recommend(svc.Replicas, average, now)
+	if replicas != svc.Replicas {
+		slog.Info("Autoscaling service", logging.Service, svc.Name,
+			"from", svc.Replicas, "to", replicas, "metric", as.Metric,
+			"average_utilization", average, "target_utilization", as.TargetUtilization)
+		svc.Replicas = replicas
+		as.LastScaleTime = now
+	}
+	m.reconcileService(svc)
+}
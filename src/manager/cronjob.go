@@ -0,0 +1,186 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/cronjob"
+	"github.com/utkarsh5026/Orchestra/logging"
+	"github.com/utkarsh5026/Orchestra/manifest"
+	"github.com/utkarsh5026/Orchestra/store"
+	"github.com/utkarsh5026/Orchestra/task"
+	"github.com/utkarsh5026/Orchestra/utils"
+)
+
+// AddCronJob admits the template of a cron job and stores the cron job so that
+// it is evaluated by RunCronJobs.
+//
+// Parameters:
+//   - ctx: Context bounding the calls of the admission webhooks
+//   - req: The cron job to create, as passed to cronjob.New
+//
+// Returns:
+//   - *cronjob.CronJob: The created cron job
+//   - error: Wrapping ErrInvalidObject if the cron job is invalid, an
+//     *AdmissionError if a webhook rejected its template, or the error of the
+//     cron job store update
+func (m *Manager) AddCronJob(ctx context.Context, req cronjob.CronJob) (*cronjob.CronJob, error) {
+	key := manifest.Key(manifest.KindCronJob, req.Name)
+	if err := m.AdmitTemplate(ctx, &req.Template, key, "Template."); err != nil {
+		return nil, err
+	}
+	cj, err := cronjob.New(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidObject, err)
+	}
+
+	m.cronJobsMu.Lock()
+	defer m.cronJobsMu.Unlock()
+
+	if err := m.CronJobs.Put(cj.ID.String(), cj); err != nil {
+		return nil, fmt.Errorf("failed to store cron job %s: %w", cj.ID, err)
+	}
+	return cj, nil
+}
+
+// DeleteCronJob removes a cron job. Tasks it already started keep running.
+//
+// Parameters:
+//   - id: The ID of the cron job
+//
+// Returns:
+//   - error if the cron job does not exist or cannot be deleted
+func (m *Manager) DeleteCronJob(id uuid.UUID) error {
+	m.cronJobsMu.Lock()
+	defer m.cronJobsMu.Unlock()
+
+	if _, err := m.CronJobs.Get(id.String()); err != nil {
+		return err
+	}
+	return m.CronJobs.Delete(id.String())
+}
+
+// RunCronJobs periodically starts the due runs of all cron jobs.
+//
+// Parameters:
+//   - ctx: Context whose cancellation stops the loop
+//   - d: The duration to wait between evaluations; it bounds how late a run may start
+//
+// This function runs until ctx is cancelled and should be started in a separate goroutine.
+func (m *Manager) RunCronJobs(ctx context.Context, d time.Duration) {
+	for {
+		m.evaluateCronJobs(ctx, time.Now().UTC())
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d):
+		}
+	}
+}
+
+func (m *Manager) evaluateCronJobs(ctx context.Context, now time.Time) {
+	cjs, err := m.CronJobs.List()
+	if err != nil {
+		slog.Error("Error listing cron jobs", "error", err)
+		return
+	}
+
+	for _, cj := range cjs {
+		m.cronJobsMu.Lock()
+		// Skip the cron jobs deleted since they were listed.
+		if cur, err := m.CronJobs.Get(cj.ID.String()); err == nil && cur == cj {
+			m.evaluateCronJob(ctx, cj, now)
+		}
+		m.cronJobsMu.Unlock()
+	}
+}
+
+// evaluateCronJob moves finished runs to the history, trims it, and starts the
+// runs that are due according to the concurrency policy of the cron job. The
+// caller must hold cronJobsMu.
+func (m *Manager) evaluateCronJob(ctx context.Context, cj *cronjob.CronJob, now time.Time) {
+	// Finish removes the run from cj.Active, so iterate over a copy.
+	for _, id := range slices.Clone(cj.Active) {
+		t, err := m.TaskStore.Get(id.String())
+		if err != nil || !t.IsFinished() || t.CanRetry() {
+			continue
+		}
+		var kept []uuid.UUID
+		for _, old := range cj.Finish(id) {
+			if err := m.deleteCronJobRun(ctx, old); err != nil {
+				slog.Error("Error deleting task of cron job", logging.CronJob, cj.Name, logging.TaskID, old, "error", err)
+				kept = append(kept, old)
+			}
+		}
+		// Keep the runs that could not be deleted as the oldest ones, so that they
+		// are evicted again next time.
+		cj.History = append(kept, cj.History...)
+	}
+
+	due, skipped := cj.DueRuns(now)
+	if skipped > 0 {
+		slog.Warn("Cron job missed runs beyond its catch-up limit", logging.CronJob, cj.Name, "skipped", skipped)
+	}
+
+	for _, run := range due {
+		cj.LastScheduleTime = run
+		if len(cj.Active) > 0 {
+			switch cj.ConcurrencyPolicy {
+			case cronjob.Forbid:
+				slog.Info("Skipping run of cron job, previous run still active", logging.CronJob, cj.Name, "scheduled_at", run)
+				continue
+			case cronjob.Replace:
+				m.stopCronJobRuns(cj)
+			}
+		}
+
+		t := cj.NewTask()
+		cj.Active = append(cj.Active, t.ID)
+		slog.Info("Starting run of cron job", logging.CronJob, cj.Name, "scheduled_at", run, logging.TaskID, t.ID)
+		m.AddTask(task.Event{
+			ID:        uuid.New(),
+			State:     task.Scheduled,
+			Timestamp: time.Now(),
+			Task:      t,
+		})
+	}
+
+	utils.UpdateStore(m.CronJobs, cj.ID.String(), cj)
+}
+
+// deleteCronJobRun removes a run evicted from the history of a cron job from the
+// worker it ran on, then forgets it.
+func (m *Manager) deleteCronJobRun(ctx context.Context, id uuid.UUID) error {
+	if w, ok := m.workerOf(id); ok {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		if err := m.removeTask(ctx, w, id.String()); err != nil {
+			return err
+		}
+	}
+
+	m.unassignTask(id)
+	if err := m.TaskStore.Delete(id.String()); err != nil && !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	m.publishTaskDeleted(id)
+	return nil
+}
+
+// stopCronJobRuns stops the active tasks of a cron job and forgets them.
+func (m *Manager) stopCronJobRuns(cj *cronjob.CronJob) {
+	for _, id := range cj.Active {
+		t, err := m.TaskStore.Get(id.String())
+		if err != nil {
+			// The task is still waiting for a worker; stop it from its ID.
+			t = &task.Task{ID: id}
+		}
+		m.StopTask(t)
+	}
+	cj.Active = nil
+}
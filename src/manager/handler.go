@@ -2,55 +2,220 @@ package manager
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/docker/docker/api/types/registry"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/cronjob"
+	"github.com/utkarsh5026/Orchestra/flow"
+	"github.com/utkarsh5026/Orchestra/handler"
+	"github.com/utkarsh5026/Orchestra/logging"
+	"github.com/utkarsh5026/Orchestra/service"
+	"github.com/utkarsh5026/Orchestra/store"
 	"github.com/utkarsh5026/Orchestra/task"
+	"github.com/utkarsh5026/Orchestra/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/websocket"
 )
 
 // StartTaskHandler handles HTTP POST requests to create a new task.
 //
 // It expects a JSON request body containing a task.Event object. The handler will:
 // 1. Decode the JSON request body into a task.Event
-// 2. Add the task event to the manager's pending queue
-// 3. Return the created task with 201 Created status
+// 2. Admit the event with Manager.SubmitTask, which assigns missing IDs, calls
+// the admission webhooks and validates the task
+// 3. Add the task event to the manager's pending queue with the request's trace
+// 4. Return the created task with 201 Created status
+//
+// Scheduling and starting the task are traced as part of the same request. An
+// event without a timestamp is stamped with the time it was received, which
+// orders it in the event history of the task.
 //
 // Returns:
-//   - 201 Created with the created task on success
-//   - 400 Bad Request if the request body is invalid or malformed
+//   - 201 Created with the created task, including its assigned ID, on success
+//   - 400 Bad Request if the request body is malformed, or listing the invalid
+//     fields if the event is rejected
+//   - 403 Forbidden if an admission webhook rejects the task
+//   - 503 Service Unavailable if an admission webhook that must admit the task
+//     cannot be called
 func (a *Api) StartTaskHandler(w http.ResponseWriter, r *http.Request) {
 	d := json.NewDecoder(r.Body)
 	d.DisallowUnknownFields()
 
 	var te task.Event
 	if err := d.Decode(&te); err != nil {
-		http.Error(w, fmt.Sprintf("Error decoding task event: %v", err), http.StatusBadRequest)
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Error decoding task event", err))
 		return
 	}
 
-	a.Manager.AddTask(te)
-	log.Printf("Task event added: %v", te)
+	te, err := a.queueTaskEvent(r, "StartTaskHandler", te)
+	if err != nil {
+		handler.SendErr(w, submitErr(err, nil))
+		return
+	}
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(te.Task)
 }
 
-// GetTasksHandler handles HTTP GET requests to retrieve all tasks.
+// queueTaskEvent submits a task event to the manager's pending queue, carrying
+// the trace of the request so that scheduling and starting the task join it.
+// It returns the queued event, or the error of Manager.SubmitTask if the event
+// was rejected.
+func (a *Api) queueTaskEvent(r *http.Request, operation string, te task.Event) (task.Event, error) {
+	ctx, span := tracer.Start(r.Context(), operation, trace.WithAttributes(
+		attribute.String("task.image", te.Task.Image),
+	))
+	defer span.End()
+	te.TraceContext = tracing.Inject(ctx)
+
+	te, err := a.Manager.SubmitTask(ctx, te)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		slog.Warn("Task event rejected", logging.TaskID, te.Task.ID, logging.EventID, te.ID, "error", err)
+		return te, err
+	}
+	span.SetAttributes(attribute.String("task.id", te.Task.ID.String()))
+	slog.Info("Task event added", logging.TaskID, te.Task.ID, logging.EventID, te.ID, "state", te.State)
+	return te, nil
+}
+
+// sendLocked writes v as the JSON body of a response, encoding it while mu is
+// held for reading since the manager's loops modify services, cron jobs and
+// workflows in place.
+func sendLocked(w http.ResponseWriter, status int, mu *sync.RWMutex, v any) {
+	mu.RLock()
+	b, err := json.Marshal(v)
+	mu.RUnlock()
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusInternalServerError, "Error encoding response", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(b, '\n'))
+}
+
+// submitErr converts an error of Manager.SubmitTask into the response sent to
+// the client.
+//
+// Parameters:
+//   - err: The error of SubmitTask
+//   - rename: Renames the invalid fields of a *task.ValidationError after the fields
+//     of the request; nil keeps the names of the fields of task.Event
+//
+// Returns:
+//   - handler.ResponseError: 400 listing the invalid fields, 403 with the reasons
+//     of a webhook that rejected the task, 503 if a webhook could not be
+//     called, or 500 otherwise
+func submitErr(err error, rename func([]handler.FieldError) []handler.FieldError) handler.ResponseError {
+	var invalid *task.ValidationError
+	var denied *AdmissionError
+	switch {
+	case errors.As(err, &invalid):
+		fields := invalid.Fields
+		if rename != nil {
+			fields = rename(fields)
+		}
+		return handler.ValidationErr("Invalid task", fields)
+	case errors.As(err, &denied):
+		return admissionErr(denied, err)
+	default:
+		return handler.Err(http.StatusInternalServerError, "Error submitting task", err)
+	}
+}
+
+// admissionErr converts err, wrapping denied, into a 403 with the reasons of the
+// webhook that rejected a task or template, or a 503 if the webhook could not
+// be called.
+func admissionErr(denied *AdmissionError, err error) handler.ResponseError {
+	if denied.Err != nil {
+		return handler.Err(http.StatusServiceUnavailable, "Admission webhook failed", err)
+	}
+	e := handler.Err(http.StatusForbidden, "Task denied by admission webhook", err)
+	e.Fields = denied.Fields
+	return e
+}
+
+// objectErr converts an error creating or updating a service, cron job or
+// workflow into the response sent to the client.
+//
+// Parameters:
+//   - invalid: The message of the 400 sent if the object is invalid
+//   - failed: The message of the 500 sent for any other error
+//   - err: The error of the Manager method
+//
+// Returns:
+//   - handler.ResponseError: 400 as built by invalidErr, 403 or 503 as built by
+//     admissionErr, or 500 otherwise
+func objectErr(invalid, failed string, err error) handler.ResponseError {
+	var denied *AdmissionError
+	var fields *task.ValidationError
+	switch {
+	case errors.As(err, &denied):
+		return admissionErr(denied, err)
+	case errors.Is(err, ErrInvalidObject), errors.As(err, &fields):
+		return invalidErr(invalid, err)
+	default:
+		return handler.Err(http.StatusInternalServerError, failed, err)
+	}
+}
+
+// invalidErr converts an error validating a service, cron job, workflow or
+// manifest into a 400 response, listing the invalid fields of its task
+// template if there are any.
+func invalidErr(message string, err error) handler.ResponseError {
+	var invalid *task.ValidationError
+	if !errors.As(err, &invalid) {
+		return handler.Err(http.StatusBadRequest, message, err)
+	}
+	e := handler.ValidationErr(message, invalid.Fields)
+	e.Details = err.Error()
+	return e
+}
+
+// GetTasksHandler handles HTTP GET requests to list tasks.
+//
+// The tasks can be filtered, sorted and paged with the query parameters read by
+// task.ParseQuery, and restricted to the tasks assigned to one worker with the
+// worker parameter.
 //
 // The handler will:
-// 1. Get all tasks from the manager's task store
-// 2. Return the tasks as a JSON array with 200 OK status
+// 1. Parse the query parameters
+// 2. Select the matching tasks from the manager's task store
+// 3. Return a page of the tasks as a JSON array with 200 OK status, the cursor of
+// the next page in the X-Next-Cursor header and the number of matching tasks in
+// the X-Total-Count header
 //
 // Returns:
-//   - 200 OK with JSON array of all tasks
+//   - 200 OK with JSON array of the tasks of the page
+//   - 400 Bad Request if a query parameter or the cursor is invalid
+//   - 500 Internal Server Error if the task store cannot be read
 func (a *Api) GetTasksHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	tasks := a.Manager.GetTasks()
-	json.NewEncoder(w).Encode(tasks)
+	q, err := task.ParseQuery(r.URL.Query())
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid task query", err))
+		return
+	}
+
+	tasks, next, total, err := a.Manager.ListTasks(q, r.URL.Query().Get("worker"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusInternalServerError, "Error getting tasks", err))
+		return
+	}
+
+	handler.SendPage(w, tasks, next, total)
 }
 
 // StopTaskHandler handles HTTP DELETE requests to stop a running task.
@@ -61,7 +226,7 @@ func (a *Api) GetTasksHandler(w http.ResponseWriter, r *http.Request) {
 //
 // The handler will:
 // 1. Extract and validate the task ID from the URL path
-// 2. Look up the task in the manager's task store
+// 2. Look up the task in the manager's task store or pending queue
 // 3. Create a new task event with Completed state
 // 4. Add the event to the manager's pending queue
 //
@@ -82,23 +247,826 @@ func (a *Api) StopTaskHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	taskToStop, ok := a.Manager.TaskStore[tID]
-	if !ok {
+	t, err := a.Manager.GetTask(tID)
+	if err != nil {
 		http.Error(w, "Task not found", http.StatusNotFound)
 		return
 	}
 
-	te := task.Event{
-		ID:        uuid.New(),
-		State:     task.Completed,
-		Timestamp: time.Now(),
+	// A task still waiting for a worker is cancelled when its start is dequeued.
+	te := a.Manager.StopTask(&t.Task)
+	slog.Info("Task stop requested", logging.TaskID, te.Task.ID, logging.EventID, te.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetStatsHandler handles HTTP GET requests for the usage history of every
+// sampled task, collected from all workers.
+//
+// Returns:
+//   - 200 OK with a JSON array of task stats, each naming the worker that reported it
+func (a *Api) GetStatsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(a.Manager.GetAllStats(r.Context()))
+}
+
+// GetTaskStatsHandler handles HTTP GET requests for the usage history of a task.
+//
+// The CPU, memory, network and block IO samples are fetched from the worker
+// running the task, together with their averages and peaks.
+//
+// Returns:
+//   - 200 OK with the task stats
+//   - 400 Bad Request if the task ID is invalid
+//   - 404 Not Found if no usage has been recorded for the task
+//   - 502 Bad Gateway if the worker running the task cannot be reached
+func (a *Api) GetTaskStatsHandler(w http.ResponseWriter, r *http.Request) {
+	tID, err := uuid.Parse(chi.URLParam(r, "taskID"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid task ID", err))
+		return
+	}
+
+	stats, err := a.Manager.GetTaskStats(r.Context(), tID)
+	if errors.Is(err, ErrNoStats) {
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "No stats recorded for task", err))
+		return
+	}
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadGateway, "Error getting stats from worker", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(stats)
+}
+
+// GetTaskHandler handles HTTP GET requests for a single task.
+//
+// Returns:
+//   - 200 OK with the task and the worker it is assigned to
+//   - 400 Bad Request if the task ID is invalid
+//   - 404 Not Found if the task is neither scheduled on a worker nor waiting for one
+func (a *Api) GetTaskHandler(w http.ResponseWriter, r *http.Request) {
+	tID, err := uuid.Parse(chi.URLParam(r, "taskID"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid task ID", err))
+		return
+	}
+
+	t, err := a.Manager.GetTask(tID)
+	if errors.Is(err, store.ErrNotFound) {
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "Task not found", err))
+		return
+	}
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusInternalServerError, "Error getting task", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(t)
+}
+
+// GetTaskEventsHandler handles HTTP GET requests for the event history of a task.
+//
+// Returns:
+//   - 200 OK with a JSON array of the task's dispatched events, oldest first
+//   - 400 Bad Request if the task ID is invalid
+//   - 404 Not Found if the manager knows neither the task nor any of its events
+//   - 500 Internal Server Error if the event store cannot be read
+func (a *Api) GetTaskEventsHandler(w http.ResponseWriter, r *http.Request) {
+	tID, err := uuid.Parse(chi.URLParam(r, "taskID"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid task ID", err))
+		return
+	}
+
+	events, err := a.Manager.TaskEvents(tID)
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusInternalServerError, "Error getting task events", err))
+		return
+	}
+	if len(events) == 0 {
+		if _, err := a.Manager.GetTask(tID); errors.Is(err, store.ErrNotFound) {
+			handler.SendErr(w, handler.Err(http.StatusNotFound, "Task not found", err))
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(events)
+}
+
+// GetTaskLogsHandler handles HTTP GET requests for the output of a task's container.
+//
+// The logs are streamed from the worker running the task as plain text. With
+// ?follow=true the stream stays open until the container exits or the client
+// disconnects; ?tail=N limits the output to its last N lines.
+//
+// Returns:
+//   - 200 OK with the log stream
+//   - 400 Bad Request if the task ID or follow parameter is invalid
+//   - 404 Not Found if the task is not running on a worker or has no container
+//   - 502 Bad Gateway if the worker running the task cannot be reached
+func (a *Api) GetTaskLogsHandler(w http.ResponseWriter, r *http.Request) {
+	tID, err := uuid.Parse(chi.URLParam(r, "taskID"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid task ID", err))
+		return
+	}
+
+	opts := task.LogOptions{Tail: r.URL.Query().Get("tail")}
+	if f := r.URL.Query().Get("follow"); f != "" {
+		if opts.Follow, err = strconv.ParseBool(f); err != nil {
+			handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid follow parameter", err))
+			return
+		}
+	}
+	a.sendTaskLogs(w, r, tID, opts)
+}
+
+// sendTaskLogs streams the logs of a task from the worker running it.
+func (a *Api) sendTaskLogs(w http.ResponseWriter, r *http.Request, tID uuid.UUID, opts task.LogOptions) {
+	rc, err := a.Manager.TaskLogs(r.Context(), tID, opts)
+	if errors.Is(err, ErrNoLogs) {
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "No logs for task", err))
+		return
+	}
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadGateway, "Error getting logs from worker", err))
+		return
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(handler.FlushWriter(w), rc); err != nil && r.Context().Err() == nil {
+		slog.Error("Error sending task logs", logging.TaskID, tID, "error", err)
+	}
+}
+
+// watchKeepAlive is how often an idle server-sent event stream sends a comment
+// so that proxies and clients do not time it out.
+const watchKeepAlive = 15 * time.Second
+
+// WatchTasksHandler handles HTTP GET requests to stream task changes.
+//
+// The stream is sent as server-sent events, or as JSON text messages over a
+// WebSocket if the request asks to upgrade. Every message is a WatchEvent; with
+// server-sent events its revision is the event ID and its type the event name.
+//
+// Query parameters:
+//   - revision: Replay the changes after this revision before streaming new
+//     ones; the Last-Event-ID header of a reconnecting event source is used if
+//     it is absent. Without either only new changes are streamed.
+//   - task: Only stream the changes of this task
+//
+// The revision of the last change before the stream started is sent in the
+// X-Revision header.
+//
+// Returns:
+//   - 200 OK with a stream of server-sent events
+//   - 101 Switching Protocols for a WebSocket
+//   - 400 Bad Request if the revision or task ID is invalid
+//   - 410 Gone if the changes after the revision are no longer kept; the client
+//     should list the tasks again and watch without a revision
+func (a *Api) WatchTasksHandler(w http.ResponseWriter, r *http.Request) {
+	a.watchTasks(w, r, func(e WatchEvent) any { return e })
+}
+
+// watchTasks streams the task changes requested by the revision and task
+// parameters, encoding each watch event with encode.
+func (a *Api) watchTasks(w http.ResponseWriter, r *http.Request, encode func(WatchEvent) any) {
+	from := r.URL.Query().Get("revision")
+	if from == "" {
+		from = r.Header.Get("Last-Event-ID")
+	}
+	var revision uint64
+	if from != "" {
+		var err error
+		if revision, err = strconv.ParseUint(from, 10, 64); err != nil {
+			handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid revision", err))
+			return
+		}
+	}
+
+	var taskID uuid.UUID
+	if id := r.URL.Query().Get("task"); id != "" {
+		var err error
+		if taskID, err = uuid.Parse(id); err != nil {
+			handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid task ID", err))
+			return
+		}
+	}
+
+	watcher, current, err := a.Manager.Watch(revision, from != "")
+	if errors.Is(err, ErrRevisionGone) {
+		handler.SendErr(w, handler.Err(http.StatusGone, "Revision is no longer available", err))
+		return
+	}
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusInternalServerError, "Error watching tasks", err))
+		return
+	}
+	defer watcher.Stop()
+
+	events := make(chan WatchEvent)
+	go func() {
+		defer close(events)
+		for e := range watcher.C {
+			if taskID != uuid.Nil && e.taskID() != taskID {
+				continue
+			}
+			select {
+			case events <- e:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}()
+
+	w.Header().Set("X-Revision", strconv.FormatUint(current, 10))
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		websocket.Server{Handler: func(ws *websocket.Conn) { streamWebSocket(ws, events, encode) }}.ServeHTTP(w, r)
+		return
+	}
+	streamEvents(w, r, events, encode)
+}
+
+// streamEvents sends watch events as server-sent events until the client goes
+// away, the server shuts down or the watcher is closed.
+func streamEvents(w http.ResponseWriter, r *http.Request, events <-chan WatchEvent, encode func(WatchEvent) any) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	out := handler.FlushWriter(w)
+	fmt.Fprint(out, ": watching tasks\n\n")
+
+	keepAlive := time.NewTicker(watchKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(out, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(encode(e))
+			if err != nil {
+				slog.Error("Error encoding watch event", "revision", e.Revision, "error", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(out, "id: %d\nevent: %s\ndata: %s\n\n", e.Revision, e.Type, data); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// streamWebSocket sends watch events as JSON text messages until the client
+// closes the connection, the server shuts down or the watcher is closed.
+func streamWebSocket(ws *websocket.Conn, events <-chan WatchEvent, encode func(WatchEvent) any) {
+	defer ws.Close()
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		// Messages from the client are not expected; reading detects the close.
+		io.Copy(io.Discard, ws)
+	}()
+
+	ctx := ws.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-closed:
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := websocket.JSON.Send(ws, encode(e)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// GetNodesHandler handles HTTP GET requests for the worker nodes of the cluster.
+//
+// Returns:
+//   - 200 OK with a JSON array of node statuses
+func (a *Api) GetNodesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(a.Manager.Nodes())
+}
+
+// ApplyHandler handles HTTP POST requests to apply manifests.
+//
+// It expects a JSON request body containing an ApplyRequest. Objects are
+// created, updated or deleted so that they match the declared ones; with
+// DryRun set the changes are only computed, which is how a diff is previewed.
+//
+// Returns:
+//   - 200 OK with a JSON array of the changes
+//   - 400 Bad Request if the request body or a declared object is invalid
+//   - 403 Forbidden if an admission webhook rejected the template of an object
+//   - 503 Service Unavailable if an admission webhook could not be called
+//   - 500 Internal Server Error if a change could not be made
+func (a *Api) ApplyHandler(w http.ResponseWriter, r *http.Request) {
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+
+	var req ApplyRequest
+	if err := d.Decode(&req); err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Error decoding apply request", err))
+		return
+	}
+
+	changes, err := a.Manager.Apply(r.Context(), req)
+	var denied *AdmissionError
+	if errors.As(err, &denied) {
+		handler.SendErr(w, admissionErr(denied, err))
+		return
+	}
+	if errors.Is(err, ErrInvalidManifest) {
+		handler.SendErr(w, invalidErr("Error applying manifest", err))
+		return
+	}
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusInternalServerError, "Error applying manifest", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(changes)
+}
+
+// PutSecretHandler handles HTTP PUT requests to create or replace a registry secret.
+//
+// It expects a JSON request body containing the registry credentials. Secrets are
+// write-only: they are never returned by the API and are only sent to the worker
+// that runs a task referencing them through its ImagePullSecret.
+//
+// Returns:
+//   - 204 No Content on success
+//   - 400 Bad Request if the request body is invalid or malformed
+//   - 500 Internal Server Error if the secret cannot be stored
+func (a *Api) PutSecretHandler(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var auth registry.AuthConfig
+	if err := json.NewDecoder(r.Body).Decode(&auth); err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid request body", err))
+		return
+	}
+
+	if err := a.Manager.PutSecret(name, auth); err != nil {
+		handler.SendErr(w, handler.Err(http.StatusInternalServerError, "Error storing secret", err))
+		return
+	}
+
+	slog.Info("Secret stored", "secret", name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SubmitWorkflowHandler handles HTTP POST requests to run a new workflow.
+//
+// It expects a JSON request body containing the workflow name and its steps. The
+// handler will:
+// 1. Decode the request and admit the task of each step
+// 2. Validate the steps as a DAG
+// 3. Store the workflow and dispatch the steps that have no dependencies
+// 4. Return the created workflow with 201 Created status
+//
+// Returns:
+//   - 201 Created with the created workflow on success
+//   - 400 Bad Request if the request body is malformed or the steps are not a valid DAG
+//   - 403 Forbidden if an admission webhook rejected the task of a step
+//   - 503 Service Unavailable if an admission webhook could not be called
+//   - 500 Internal Server Error if the workflow cannot be stored
+func (a *Api) SubmitWorkflowHandler(w http.ResponseWriter, r *http.Request) {
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+
+	var req struct {
+		Name  string
+		Steps []*flow.Step
+	}
+	if err := d.Decode(&req); err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid request body", err))
+		return
+	}
+
+	wf, err := a.Manager.SubmitWorkflow(r.Context(), req.Name, req.Steps)
+	if err != nil {
+		handler.SendErr(w, objectErr("Invalid workflow", "Error submitting workflow", err))
+		return
+	}
+
+	slog.Info("Workflow submitted", logging.Workflow, wf.ID)
+	sendLocked(w, http.StatusCreated, &a.Manager.workflowsMu, wf)
+}
+
+// GetWorkflowsHandler handles HTTP GET requests to list all workflows.
+//
+// Returns:
+//   - 200 OK with JSON array of all workflows
+//   - 500 Internal Server Error if the workflow store cannot be read
+func (a *Api) GetWorkflowsHandler(w http.ResponseWriter, r *http.Request) {
+	wfs, err := a.Manager.GetWorkflows()
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusInternalServerError, "Error getting workflows", err))
+		return
+	}
+
+	sendLocked(w, http.StatusOK, &a.Manager.workflowsMu, wfs)
+}
+
+// GetWorkflowHandler handles HTTP GET requests for the status of a single workflow.
+//
+// Returns:
+//   - 200 OK with the workflow and the state of each of its steps
+//   - 400 Bad Request if the workflow ID is invalid
+//   - 404 Not Found if the workflow does not exist
+func (a *Api) GetWorkflowHandler(w http.ResponseWriter, r *http.Request) {
+	wfID, err := uuid.Parse(chi.URLParam(r, "workflowID"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid workflow ID", err))
+		return
+	}
+
+	wf, err := a.Manager.Workflows.Get(wfID.String())
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "Workflow not found", err))
+		return
+	}
+
+	sendLocked(w, http.StatusOK, &a.Manager.workflowsMu, wf)
+}
+
+// DeleteWorkflowHandler handles HTTP DELETE requests to remove a workflow.
+// The tasks of its running steps are stopped.
+//
+// Returns:
+//   - 204 No Content on success
+//   - 400 Bad Request if the workflow ID is invalid
+//   - 404 Not Found if the workflow does not exist
+func (a *Api) DeleteWorkflowHandler(w http.ResponseWriter, r *http.Request) {
+	wfID, err := uuid.Parse(chi.URLParam(r, "workflowID"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid workflow ID", err))
+		return
+	}
+
+	if err := a.Manager.DeleteWorkflow(wfID); err != nil {
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "Workflow not found", err))
+		return
+	}
+
+	slog.Info("Workflow deleted", logging.Workflow, wfID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateCronJobHandler handles HTTP POST requests to create a cron job.
+//
+// It expects a JSON request body containing the cron job name, schedule, task
+// template and optional concurrency policy and limits.
+//
+// Returns:
+//   - 201 Created with the created cron job on success
+//   - 400 Bad Request if the request body is malformed or the schedule or policy is invalid
+//   - 403 Forbidden if an admission webhook rejected the task template
+//   - 503 Service Unavailable if an admission webhook could not be called
+//   - 500 Internal Server Error if the cron job cannot be stored
+func (a *Api) CreateCronJobHandler(w http.ResponseWriter, r *http.Request) {
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+
+	var req cronjob.CronJob
+	if err := d.Decode(&req); err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid request body", err))
+		return
+	}
+
+	cj, err := a.Manager.AddCronJob(r.Context(), req)
+	if err != nil {
+		handler.SendErr(w, objectErr("Invalid cron job", "Error creating cron job", err))
+		return
+	}
+
+	slog.Info("Cron job created", logging.CronJob, cj.ID)
+	sendLocked(w, http.StatusCreated, &a.Manager.cronJobsMu, cj)
+}
+
+// GetCronJobsHandler handles HTTP GET requests to list all cron jobs.
+//
+// Returns:
+//   - 200 OK with JSON array of all cron jobs
+//   - 500 Internal Server Error if the cron job store cannot be read
+func (a *Api) GetCronJobsHandler(w http.ResponseWriter, r *http.Request) {
+	cjs, err := a.Manager.CronJobs.List()
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusInternalServerError, "Error getting cron jobs", err))
+		return
+	}
+
+	sendLocked(w, http.StatusOK, &a.Manager.cronJobsMu, cjs)
+}
+
+// GetCronJobHandler handles HTTP GET requests for a single cron job, including
+// its active and retained tasks.
+//
+// Returns:
+//   - 200 OK with the cron job
+//   - 400 Bad Request if the cron job ID is invalid
+//   - 404 Not Found if the cron job does not exist
+func (a *Api) GetCronJobHandler(w http.ResponseWriter, r *http.Request) {
+	cjID, err := uuid.Parse(chi.URLParam(r, "cronJobID"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid cron job ID", err))
+		return
+	}
+
+	cj, err := a.Manager.CronJobs.Get(cjID.String())
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "Cron job not found", err))
+		return
+	}
+
+	sendLocked(w, http.StatusOK, &a.Manager.cronJobsMu, cj)
+}
+
+// DeleteCronJobHandler handles HTTP DELETE requests to remove a cron job.
+// Tasks already started by the cron job keep running.
+//
+// Returns:
+//   - 204 No Content on success
+//   - 400 Bad Request if the cron job ID is invalid
+//   - 404 Not Found if the cron job does not exist
+func (a *Api) DeleteCronJobHandler(w http.ResponseWriter, r *http.Request) {
+	cjID, err := uuid.Parse(chi.URLParam(r, "cronJobID"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid cron job ID", err))
+		return
+	}
+
+	if err := a.Manager.DeleteCronJob(cjID); err != nil {
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "Cron job not found", err))
+		return
+	}
+
+	slog.Info("Cron job deleted", logging.CronJob, cjID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateServiceHandler handles HTTP POST requests to create a replicated service.
+//
+// It expects a JSON request body containing the service name, task template and
+// replica count. The replicas are started right away and kept running by the
+// reconciliation loop.
+//
+// Returns:
+//   - 201 Created with the created service on success
+//   - 400 Bad Request if the request body is malformed or the service is invalid
+//   - 403 Forbidden if an admission webhook rejected the task template
+//   - 503 Service Unavailable if an admission webhook could not be called
+//   - 500 Internal Server Error if the service cannot be stored
+func (a *Api) CreateServiceHandler(w http.ResponseWriter, r *http.Request) {
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+
+	var req service.Service
+	if err := d.Decode(&req); err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid request body", err))
+		return
+	}
+
+	svc, err := a.Manager.AddService(r.Context(), req)
+	if err != nil {
+		handler.SendErr(w, objectErr("Invalid service", "Error creating service", err))
+		return
+	}
+
+	slog.Info("Service created", logging.Service, svc.ID)
+	sendLocked(w, http.StatusCreated, &a.Manager.servicesMu, svc)
+}
+
+// GetServicesHandler handles HTTP GET requests to list all services.
+//
+// Returns:
+//   - 200 OK with JSON array of all services
+//   - 500 Internal Server Error if the service store cannot be read
+func (a *Api) GetServicesHandler(w http.ResponseWriter, r *http.Request) {
+	svcs, err := a.Manager.Services.List()
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusInternalServerError, "Error getting services", err))
+		return
+	}
+
+	sendLocked(w, http.StatusOK, &a.Manager.servicesMu, svcs)
+}
+
+// GetServiceHandler handles HTTP GET requests for a single service and its tasks.
+//
+// Returns:
+//   - 200 OK with the service
+//   - 400 Bad Request if the service ID is invalid
+//   - 404 Not Found if the service does not exist
+func (a *Api) GetServiceHandler(w http.ResponseWriter, r *http.Request) {
+	svcID, err := uuid.Parse(chi.URLParam(r, "serviceID"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid service ID", err))
+		return
+	}
+
+	svc, err := a.Manager.Services.Get(svcID.String())
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "Service not found", err))
+		return
+	}
+
+	sendLocked(w, http.StatusOK, &a.Manager.servicesMu, svc)
+}
+
+// ScaleServiceHandler handles HTTP PUT requests to change the replica count of a service.
+//
+// It expects a JSON request body of the form {"Replicas": n}.
+//
+// Returns:
+//   - 200 OK with the updated service
+//   - 400 Bad Request if the service ID, request body or replica count is invalid
+//   - 404 Not Found if the service does not exist
+func (a *Api) ScaleServiceHandler(w http.ResponseWriter, r *http.Request) {
+	svcID, err := uuid.Parse(chi.URLParam(r, "serviceID"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid service ID", err))
+		return
+	}
+
+	var req struct{ Replicas int }
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid request body", err))
+		return
+	}
+	if req.Replicas < 0 {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid replica count", nil))
+		return
+	}
+
+	svc, err := a.Manager.ScaleService(svcID, req.Replicas)
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "Service not found", err))
+		return
+	}
+
+	slog.Info("Service scaled", logging.Service, svc.ID, "replicas", req.Replicas)
+	sendLocked(w, http.StatusOK, &a.Manager.servicesMu, svc)
+}
+
+// UpdateServiceHandler handles HTTP PUT requests to change the template of a service.
+//
+// It expects a JSON request body containing the new task template and, optionally,
+// a new rolling update strategy. The template becomes a new revision and the
+// replicas are replaced gradually according to the strategy.
+//
+// Returns:
+//   - 200 OK with the updated service
+//   - 400 Bad Request if the service ID, request body, template or strategy is invalid
+//   - 403 Forbidden if an admission webhook rejected the template
+//   - 404 Not Found if the service does not exist
+//   - 503 Service Unavailable if an admission webhook could not be called
+//   - 500 Internal Server Error if the service cannot be updated
+func (a *Api) UpdateServiceHandler(w http.ResponseWriter, r *http.Request) {
+	svcID, err := uuid.Parse(chi.URLParam(r, "serviceID"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid service ID", err))
+		return
+	}
+
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+
+	var req struct {
+		Template task.Task
+		Strategy *service.Strategy
+	}
+	if err := d.Decode(&req); err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid request body", err))
+		return
+	}
+
+	svc, err := a.Manager.UpdateService(r.Context(), svcID, req.Template, req.Strategy)
+	if errors.Is(err, store.ErrNotFound) {
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "Service not found", err))
+		return
+	}
+	if err != nil {
+		handler.SendErr(w, objectErr("Invalid service update", "Error updating service", err))
+		return
+	}
+
+	a.Manager.servicesMu.RLock()
+	revision := svc.Revision
+	a.Manager.servicesMu.RUnlock()
+	slog.Info("Service updated", logging.Service, svc.ID, "revision", revision)
+	sendLocked(w, http.StatusOK, &a.Manager.servicesMu, svc)
+}
+
+// RollbackServiceHandler handles HTTP POST requests to roll a service back to an
+// earlier revision.
+//
+// It accepts an optional JSON request body of the form {"Revision": n}; without it
+// the service is rolled back to the revision before the current one.
+//
+// Returns:
+//   - 200 OK with the updated service
+//   - 400 Bad Request if the service ID or request body is invalid, or the revision is not in the history
+//   - 404 Not Found if the service does not exist
+func (a *Api) RollbackServiceHandler(w http.ResponseWriter, r *http.Request) {
+	svcID, err := uuid.Parse(chi.URLParam(r, "serviceID"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid service ID", err))
+		return
+	}
+
+	var req struct{ Revision int }
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid request body", err))
+		return
+	}
+
+	if _, err := a.Manager.Services.Get(svcID.String()); err != nil {
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "Service not found", err))
+		return
 	}
 
-	taskCopy := *taskToStop
-	taskCopy.State = task.Completed
-	te.Task = taskCopy
+	svc, err := a.Manager.RollbackService(svcID, req.Revision)
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid rollback", err))
+		return
+	}
+
+	a.Manager.servicesMu.RLock()
+	revision := svc.Revision
+	a.Manager.servicesMu.RUnlock()
+	slog.Info("Service rolled back", logging.Service, svc.ID, "revision", revision)
+	sendLocked(w, http.StatusOK, &a.Manager.servicesMu, svc)
+}
+
+// ResumeServiceHandler handles HTTP POST requests to resume the paused rollout of a service.
+//
+// Returns:
+//   - 200 OK with the updated service
+//   - 400 Bad Request if the service ID is invalid
+//   - 404 Not Found if the service does not exist
+func (a *Api) ResumeServiceHandler(w http.ResponseWriter, r *http.Request) {
+	svcID, err := uuid.Parse(chi.URLParam(r, "serviceID"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid service ID", err))
+		return
+	}
+
+	svc, err := a.Manager.ResumeService(svcID)
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "Service not found", err))
+		return
+	}
+
+	slog.Info("Rollout of service resumed", logging.Service, svc.ID)
+	sendLocked(w, http.StatusOK, &a.Manager.servicesMu, svc)
+}
+
+// DeleteServiceHandler handles HTTP DELETE requests to remove a service and stop its tasks.
+//
+// Returns:
+//   - 204 No Content on success
+//   - 400 Bad Request if the service ID is invalid
+//   - 404 Not Found if the service does not exist
+func (a *Api) DeleteServiceHandler(w http.ResponseWriter, r *http.Request) {
+	svcID, err := uuid.Parse(chi.URLParam(r, "serviceID"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid service ID", err))
+		return
+	}
+
+	if err := a.Manager.DeleteService(svcID); err != nil {
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "Service not found", err))
+		return
+	}
 
-	a.Manager.AddTask(te)
-	log.Printf("Task stopped: %v", te)
+	slog.Info("Service deleted", logging.Service, svcID)
 	w.WriteHeader(http.StatusNoContent)
 }
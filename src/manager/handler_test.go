@@ -0,0 +1,65 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+func TestTaskHandlersFindTasksWaitingForWorker(t *testing.T) {
+	m := newTestManager()
+	a := &Api{Manager: m}
+	a.initRouter()
+
+	e := startEvent()
+	m.AddTask(e)
+	// No worker can be selected, so the task backs off in the pending queue.
+	if err := m.SendWork(context.Background()); err == nil {
+		t.Fatal("SendWork() error = nil, want the dispatch to fail")
+	}
+	path := "/tasks/" + e.Task.ID.String()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+	}{
+		{name: "get task", method: http.MethodGet, path: path, want: http.StatusOK},
+		{name: "get events", method: http.MethodGet, path: path + "/events", want: http.StatusOK},
+		{name: "get v1 task", method: http.MethodGet, path: "/v1" + path, want: http.StatusOK},
+		{name: "stop task", method: http.MethodDelete, path: path, want: http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			a.Router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			if rec.Code != tt.want {
+				t.Errorf("%s %s = %d, want %d: %s", tt.method, tt.path, rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	a.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var d TaskDetails
+	if err := json.NewDecoder(rec.Body).Decode(&d); err != nil {
+		t.Fatalf("decoding task: %v", err)
+	}
+	if d.State != task.Pending || d.SchedulingAttempts != 1 || d.NextAttempt == nil {
+		t.Errorf("task is %s after %d attempts, next at %v, want Pending after 1 attempt", d.State, d.SchedulingAttempts, d.NextAttempt)
+	}
+
+	sendAll(t, m, 5)
+	got, err := m.GetTask(e.Task.ID)
+	if err != nil {
+		t.Fatalf("GetTask() of the stopped task error = %v", err)
+	}
+	if got.State != task.Completed || got.Reason != task.ReasonCancelled {
+		t.Errorf("task is %s (%q), want Completed (%q)", got.State, got.Reason, task.ReasonCancelled)
+	}
+}
@@ -0,0 +1,63 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// ErrNoLogs is returned when a task has no container whose logs could be read.
+var ErrNoLogs = errors.New("no logs for task")
+
+// TaskLogs opens the log stream of a task on the worker running it. The stream
+// carries the container's stdout and stderr as plain text.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the stream
+//   - id: The ID of the task
+//   - opts: Which part of the output to return
+//
+// Returns:
+//   - io.ReadCloser: The log stream; the caller must close it
+//   - error: ErrNoLogs if the task is not assigned to a worker or has no container,
+//     or an error if the worker cannot be reached
+func (m *Manager) TaskLogs(ctx context.Context, id uuid.UUID, opts task.LogOptions) (io.ReadCloser, error) {
+	w, ok := m.workerOf(id)
+	if !ok {
+		return nil, ErrNoLogs
+	}
+
+	q := url.Values{}
+	q.Set("follow", strconv.FormatBool(opts.Follow))
+	if opts.Tail != "" {
+		q.Set("tail", opts.Tail)
+	}
+	u := fmt.Sprintf("http://%s/tasks/%s/logs?%s", w, id, q.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to get logs from worker %s: %w", w, err)
+	}
+
+	resp, err := workerClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs from worker %s: %w", w, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNoLogs
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("error getting logs from worker %s: %s", w, resp.Status)
+	}
+}
@@ -2,22 +2,37 @@ package manager
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"slices"
+	"sync"
 	"time"
 
+	"github.com/utkarsh5026/Orchestra/cronjob"
+	"github.com/utkarsh5026/Orchestra/flow"
+	"github.com/utkarsh5026/Orchestra/service"
 	"github.com/utkarsh5026/Orchestra/store"
+	"github.com/utkarsh5026/Orchestra/utils"
 
+	"github.com/utkarsh5026/Orchestra/logging"
+	"github.com/utkarsh5026/Orchestra/metrics"
 	"github.com/utkarsh5026/Orchestra/node"
 	"github.com/utkarsh5026/Orchestra/scheduler"
+	"github.com/utkarsh5026/Orchestra/tracing"
 
+	"github.com/docker/docker/api/types/registry"
 	"github.com/golang-collections/collections/queue"
 	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/handler"
 	"github.com/utkarsh5026/Orchestra/task"
-	"github.com/utkarsh5026/Orchestra/worker"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Manager struct {
@@ -25,11 +40,83 @@ type Manager struct {
 	Pending       queue.Queue
 	TaskStore     store.Store[string, *task.Task]
 	EventStore    store.Store[string, *task.Event]
+	Secrets       store.Store[string, *registry.AuthConfig]
+	Workflows     store.Store[string, *flow.Workflow]
+	CronJobs      store.Store[string, *cronjob.CronJob]
+	Services      store.Store[string, *service.Service]
+	// Applied records the objects managed through manifests, keyed by "<kind>/<name>".
+	Applied       store.Store[string, *AppliedObject]
 	Workers       []string
 	WorkerTaskMap map[string][]uuid.UUID
 	TaskWorkerMap map[uuid.UUID]string
 	Scheduler     scheduler.Scheduler
 	WorkerNodes   []*node.Node
+	// WorkerLastSeen records when each worker last answered a task poll.
+	WorkerLastSeen map[string]time.Time
+	// WorkerTimeout is how long a worker may go unseen before its tasks are considered lost.
+	WorkerTimeout time.Duration
+	// Webhooks are the admission webhooks called with the tasks submitted
+	// through the API, in order.
+	Webhooks []Webhook
+
+	// mu guards WorkerTaskMap, TaskWorkerMap and WorkerLastSeen, which are shared
+	// by the background loops and the API handlers.
+	mu sync.RWMutex
+	// pendingMu guards Pending, pendingTasks and waiting.
+	pendingMu sync.Mutex
+	// pendingTasks counts the events of each task in Pending, so that a task
+	// submitted with the ID of a queued task can be rejected.
+	pendingTasks map[uuid.UUID]int
+	// waiting holds the task of the latest start event of each task in Pending,
+	// so that a task waiting for a worker can be looked up before it is stored.
+	waiting map[uuid.UUID]task.Task
+	// unschedulable tracks the tasks no worker could be selected for, which
+	// are requeued with a backoff. It is guarded by pendingMu.
+	unschedulable map[uuid.UUID]*schedulingBackoff
+	// cancelled records the tasks stopped while their start event was still
+	// queued, so that the start is dropped when it is dequeued. It is guarded
+	// by pendingMu.
+	cancelled map[uuid.UUID]bool
+	// servicesMu, cronJobsMu and workflowsMu serialize access to the services,
+	// cron jobs and workflows. Their stores hold them by pointer and they are
+	// modified in place by both the background loops and the API handlers,
+	// which hold the lock for reading while encoding an object.
+	servicesMu  sync.RWMutex
+	cronJobsMu  sync.RWMutex
+	workflowsMu sync.RWMutex
+	// applyMu serializes Apply so that concurrent applies see each other's changes.
+	applyMu sync.Mutex
+	// watches publishes task changes to the watchers of the task list.
+	watches watchHub
+}
+
+var tracer = otel.Tracer("github.com/utkarsh5026/Orchestra/manager")
+
+// workerClient sends task events to workers, propagating the trace of the
+// dispatch in the request headers.
+var workerClient = &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+
+// errWorkerUnreachable is wrapped by errors returned when a request could not reach a worker.
+var errWorkerUnreachable = errors.New("worker unreachable")
+
+// ErrNoPendingTasks is returned by SendWork when the pending queue is empty.
+var ErrNoPendingTasks = errors.New("no pending tasks")
+
+// DefaultWorkerTimeout is the WorkerTimeout of a new Manager.
+const DefaultWorkerTimeout = time.Minute
+
+// Backoff of the scheduling of a task no worker can be selected for, doubling
+// from minSchedulingBackoff with each attempt up to maxSchedulingBackoff.
+const (
+	minSchedulingBackoff = 10 * time.Second
+	maxSchedulingBackoff = 5 * time.Minute
+)
+
+// schedulingBackoff records the failed attempts to select a worker for a task.
+type schedulingBackoff struct {
+	attempts int
+	// next is when the task may be scheduled again.
+	next time.Time
 }
 
 // NewManager creates and initializes a new Manager instance.
@@ -42,51 +129,74 @@ type Manager struct {
 // Returns:
 //   - *Manager: A new Manager instance initialized with:
 func NewManager(workers []string, st scheduler.Type, storeType store.Type) *Manager {
-	ts := store.NewStore[string, *task.Task](storeType)
+	ts := store.NewCopyStore[string, task.Task](storeType)
 	es := store.NewStore[string, *task.Event](storeType)
 	wt := make(map[string][]uuid.UUID)
 	tw := make(map[uuid.UUID]string)
 
 	var workerNodes []*node.Node
+	lastSeen := make(map[string]time.Time)
 	for _, w := range workers {
 		wt[w] = []uuid.UUID{}
+		lastSeen[w] = time.Now()
 		api := fmt.Sprintf("http://%s/tasks", w)
 		n := node.NewNode(w, api, "worker")
 		workerNodes = append(workerNodes, n)
 	}
 
 	return &Manager{
-		TaskStore:     ts,
-		EventStore:    es,
-		WorkerTaskMap: wt,
-		TaskWorkerMap: tw,
-		Workers:       workers,
-		Pending:       *queue.New(),
-		WorkerNodes:   workerNodes,
-		Scheduler:     scheduler.NewScheduler(st),
+		TaskStore:      ts,
+		EventStore:     es,
+		Secrets:        store.NewStore[string, *registry.AuthConfig](storeType),
+		Workflows:      store.NewStore[string, *flow.Workflow](storeType),
+		CronJobs:       store.NewStore[string, *cronjob.CronJob](storeType),
+		Services:       store.NewStore[string, *service.Service](storeType),
+		Applied:        store.NewStore[string, *AppliedObject](storeType),
+		WorkerTaskMap:  wt,
+		TaskWorkerMap:  tw,
+		Workers:        workers,
+		Pending:        *queue.New(),
+		pendingTasks:   make(map[uuid.UUID]int),
+		waiting:        make(map[uuid.UUID]task.Task),
+		unschedulable:  make(map[uuid.UUID]*schedulingBackoff),
+		cancelled:      make(map[uuid.UUID]bool),
+		WorkerNodes:    workerNodes,
+		Scheduler:      scheduler.NewScheduler(st),
+		WorkerLastSeen: lastSeen,
+		WorkerTimeout:  DefaultWorkerTimeout,
 	}
 }
 
 // SelectWorker returns the next available worker using round-robin scheduling.
 //
 // Parameters:
+//   - ctx: Context carrying the trace of the scheduling decision
 //   - t: The task to select a worker for
 //
 // Returns:
 //   - *node.Node: The selected worker node
 //   - An error if no workers are available
-func (m *Manager) SelectWorker(t task.Task) (*node.Node, error) {
-	candidates := m.Scheduler.SelectCandidates(t, m.WorkerNodes)
+func (m *Manager) SelectWorker(ctx context.Context, t task.Task) (*node.Node, error) {
+	_, span := tracer.Start(ctx, "SelectWorker", trace.WithAttributes(attribute.String("task.id", t.ID.String())))
+	defer span.End()
+
+	candidates := m.Scheduler.SelectCandidates(t, m.liveWorkerNodes())
+	span.SetAttributes(attribute.Int("scheduler.candidates", len(candidates)))
 	if candidates == nil {
-		return nil, fmt.Errorf("No candidates found to satisfy task requirements for the task %v\n", t.ID)
+		err := fmt.Errorf("No candidates found to satisfy task requirements for the task %v\n", t.ID)
+		tracing.RecordError(span, err)
+		return nil, err
 	}
 
 	scores := m.Scheduler.Score(t, candidates)
 	if scores == nil {
-		return nil, fmt.Errorf("No scores found for the task %v\n", t.ID)
+		err := fmt.Errorf("No scores found for the task %v\n", t.ID)
+		tracing.RecordError(span, err)
+		return nil, err
 	}
 
 	selected := m.Scheduler.Pick(scores, candidates)
+	span.SetAttributes(attribute.String("scheduler.worker", selected.Name))
 	return selected, nil
 }
 
@@ -101,78 +211,479 @@ func (m *Manager) SelectWorker(t task.Task) (*node.Node, error) {
 //
 // Any errors communicating with workers or tasks not found in the store are logged
 // but do not stop processing of other workers/tasks.
-func (m *Manager) UpdateTasks() {
+//
+// Failed jobs with retries left under their BackoffLimit are rescheduled, and
+// finished jobs whose TTLAfterFinished has expired are removed.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the requests to the workers
+func (m *Manager) UpdateTasks(ctx context.Context) {
 	for _, w := range m.Workers {
-		log.Printf("Checking worker for task updates: %s", w)
-		tasks, err := m.getTasksFromWorker(w)
+		slog.Debug("Checking worker for task updates", logging.Worker, w)
+		tasks, err := m.getTasksFromWorker(ctx, w)
 		if err != nil {
-			log.Printf("Error getting tasks from worker %s: %s", w, err)
+			slog.Error("Error getting tasks from worker", logging.Worker, w, "error", err)
+			metrics.WorkerPollErrors.WithLabelValues(w).Inc()
+			metrics.WorkerUp.WithLabelValues(w).Set(0)
 			continue
 		}
+		m.markSeen(w)
+		metrics.WorkerUp.WithLabelValues(w).Set(1)
 
 		for _, t := range tasks {
+			if tw, _ := m.workerOf(t.ID); tw != w {
+				continue
+			}
+
 			old, err := m.TaskStore.Get(t.ID.String())
 			if err != nil {
-				log.Printf("Task %s not found in task store", t.ID)
+				slog.Warn("Task not found in task store", logging.Worker, w, logging.TaskID, t.ID)
 				continue
 			}
 			if err := m.updateTask(old, t); err != nil {
-				log.Printf("Error updating task %s: %s", t.ID, err)
+				slog.Error("Error updating task", logging.Worker, w, logging.TaskID, t.ID, "error", err)
+				continue
+			}
+
+			if old.CanRetry() {
+				if err := m.retryJob(old); err != nil {
+					slog.Error("Error retrying job", logging.TaskID, t.ID, "error", err)
+				}
+			}
+		}
+	}
+
+	m.markLostTasks()
+	m.cleanupExpiredJobs()
+	m.recordTaskStates()
+}
+
+// recordTaskStates updates the task metrics from the states of the stored tasks.
+func (m *Manager) recordTaskStates() {
+	tasks, err := m.TaskStore.List()
+	if err != nil {
+		slog.Error("Error listing tasks", "error", err)
+		return
+	}
+
+	counts := make(map[task.State]int, len(task.States))
+	for _, t := range tasks {
+		counts[t.State]++
+	}
+	for _, s := range task.States {
+		metrics.ManagerTasks.WithLabelValues(s.String()).Set(float64(counts[s]))
+	}
+}
+
+// PollWorkers calls UpdateTasks periodically until ctx is cancelled.
+//
+// Parameters:
+//   - ctx: Context whose cancellation stops the loop
+//   - d: The duration to wait between polls
+//
+// This function runs until ctx is cancelled and should be started in a separate goroutine.
+func (m *Manager) PollWorkers(ctx context.Context, d time.Duration) {
+	for {
+		m.UpdateTasks(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d):
+		}
+	}
+}
+
+// IsWorkerLost reports whether a worker has not answered a task poll within WorkerTimeout.
+func (m *Manager) IsWorkerLost(w string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return time.Since(m.WorkerLastSeen[w]) > m.WorkerTimeout
+}
+
+// liveWorkerNodes returns the worker nodes that are not lost.
+func (m *Manager) liveWorkerNodes() []*node.Node {
+	var nodes []*node.Node
+	for _, n := range m.WorkerNodes {
+		if !m.IsWorkerLost(n.Name) {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// markLostTasks fails the unfinished tasks of lost workers and unassigns them, so
+// that jobs are retried elsewhere and services replace them.
+func (m *Manager) markLostTasks() {
+	for _, w := range m.Workers {
+		if !m.IsWorkerLost(w) {
+			continue
+		}
+
+		for _, id := range m.tasksOn(w) {
+			t, err := m.TaskStore.Get(id.String())
+			if err != nil || t.IsFinished() {
+				continue
+			}
+
+			slog.Warn("Worker is lost, failing task", logging.Worker, w, logging.TaskID, id)
+			t.State = task.Failed
+			t.Reason = task.ReasonWorkerLost
+			t.EndTime = time.Now().UTC()
+			if t.CanRetry() {
+				if err := m.retryJob(t); err != nil {
+					slog.Error("Error retrying job", logging.TaskID, id, "error", err)
+				}
+				continue
+			}
+
+			m.unassignTask(id)
+			utils.UpdateStore(m.TaskStore, id.String(), t)
+			m.publishTask(t)
+		}
+	}
+}
+
+// retryJob unassigns a failed job from its worker and queues it to be scheduled
+// again, usually on another worker.
+//
+// Parameters:
+//   - t: The failed job to retry
+//
+// Returns:
+//   - error if the task store update fails
+func (m *Manager) retryJob(t *task.Task) error {
+	m.unassignTask(t.ID)
+
+	t.Attempts++
+	t.State = task.Pending
+	t.ContainerID = ""
+	t.ExitCode = 0
+	t.Reason = ""
+	if err := m.TaskStore.Put(t.ID.String(), t); err != nil {
+		return fmt.Errorf("failed to update task %s: %w", t.ID, err)
+	}
+	m.publishTask(t)
+
+	slog.Info("Retrying job", logging.TaskID, t.ID, "attempt", t.Attempts, "backoff_limit", t.BackoffLimit)
+	m.AddTask(task.Event{
+		ID:        uuid.New(),
+		State:     task.Scheduled,
+		Timestamp: time.Now(),
+		Task:      *t,
+	})
+	return nil
+}
+
+// cleanupExpiredJobs removes finished jobs whose TTLAfterFinished has expired.
+// Workers remove the jobs' containers on their own. Jobs are kept while their
+// owner still needs them, see ownedTasks.
+func (m *Manager) cleanupExpiredJobs() {
+	tasks, err := m.TaskStore.List()
+	if err != nil {
+		slog.Error("Error listing tasks", "error", err)
+		return
+	}
+	owned, err := m.ownedTasks()
+	if err != nil {
+		slog.Error("Error listing the owners of tasks", "error", err)
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, t := range tasks {
+		if !t.Expired(now) || owned[t.ID] {
+			continue
+		}
+
+		m.unassignTask(t.ID)
+		if err := m.TaskStore.Delete(t.ID.String()); err != nil {
+			slog.Error("Error deleting expired job", logging.TaskID, t.ID, "error", err)
+			continue
+		}
+		m.publishTaskDeleted(t.ID)
+		slog.Info("Cleaned up job after its TTL expired", logging.TaskID, t.ID)
+	}
+}
+
+// ownedTasks returns the IDs of the jobs that must outlive their TTL because
+// their owner still needs them: the active runs of the cron jobs, which they
+// have not seen finish yet, and the tasks of the steps of unfinished workflows,
+// whose states and outputs the steps and their downstream steps are built from.
+// Removing one earlier would leave its owner waiting for it forever.
+func (m *Manager) ownedTasks() (map[uuid.UUID]bool, error) {
+	owned := make(map[uuid.UUID]bool)
+
+	cjs, err := m.CronJobs.List()
+	if err != nil {
+		return nil, err
+	}
+	m.cronJobsMu.RLock()
+	for _, cj := range cjs {
+		for _, id := range cj.Active {
+			owned[id] = true
+		}
+	}
+	m.cronJobsMu.RUnlock()
+
+	wfs, err := m.Workflows.List()
+	if err != nil {
+		return nil, err
+	}
+	m.workflowsMu.RLock()
+	for _, wf := range wfs {
+		if wf.IsFinished() {
+			continue
+		}
+		for _, s := range wf.Steps {
+			if s.TaskID != uuid.Nil {
+				owned[s.TaskID] = true
 			}
 		}
 	}
+	m.workflowsMu.RUnlock()
+
+	return owned, nil
+}
+
+// unassignTask removes a task from the worker mappings.
+func (m *Manager) unassignTask(id uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w, ok := m.TaskWorkerMap[id]
+	if !ok {
+		return
+	}
+
+	delete(m.TaskWorkerMap, id)
+	m.WorkerTaskMap[w] = slices.DeleteFunc(m.WorkerTaskMap[w], func(tid uuid.UUID) bool {
+		return tid == id
+	})
 }
 
 // SendWork dequeues a pending task and sends it to an available worker
+// The dispatch is traced as part of the request that queued the event
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the request to the worker
 //
 // Returns:
 //   - error if there are no pending tasks, no available workers,
 //     task marshaling fails, or sending to worker fails
-func (m *Manager) SendWork() error {
-	if m.Pending.Len() == 0 {
-		return errors.New("no pending tasks")
+func (m *Manager) SendWork(ctx context.Context) error {
+	e, ok := m.dequeue()
+	if !ok {
+		return ErrNoPendingTasks
+	}
+	if m.dropCancelled(e) || m.backingOff(e) {
+		return nil
 	}
 
-	e := m.Pending.Dequeue().(task.Event)
+	ctx, span := tracer.Start(tracing.Extract(ctx, e.TraceContext), "SendWork",
+		trace.WithAttributes(attribute.String("task.id", e.Task.ID.String())))
+	defer span.End()
+
+	err := m.sendWork(ctx, e)
+	tracing.RecordError(span, err)
+	return err
+}
+
+// sendWork dispatches a dequeued task event: a stop event is sent to the worker
+// running the task, any other event is scheduled on a newly selected worker.
+func (m *Manager) sendWork(ctx context.Context, e task.Event) error {
 	err := m.EventStore.Put(e.ID.String(), &e)
 	if err != nil {
 		return fmt.Errorf("failed to persist task event: %w", err)
 	}
-	log.Printf("Sending task %s to worker\n", e.Task.ID)
+	m.publishEvent(&e)
+	slog.Debug("Sending task event", logging.TaskID, e.Task.ID, logging.EventID, e.ID, "state", e.State)
 
 	taskID := e.Task.ID
-	taskWorker, ok := m.TaskWorkerMap[taskID]
+	taskWorker, ok := m.workerOf(taskID)
 	if ok {
 		pt, err := m.TaskStore.Get(taskID.String())
 		if err != nil {
 			return fmt.Errorf("failed to get persisted task %s: %w", taskID, err)
 		}
 
-		if e.State == task.Completed && pt.State.CanTransitionTo(e.State) {
-			return m.stopTask(taskWorker, taskID.String())
+		// A Scheduled task may not have started on the worker yet; stopping it
+		// cancels its start there rather than moving it to Completed.
+		if e.State == task.Completed && (pt.State == task.Scheduled || pt.State.CanTransitionTo(e.State)) {
+			return m.stopTask(ctx, taskWorker, taskID.String())
 		}
 		return fmt.Errorf("invalid request: existing task %s is in state %v and cannot transition to the completed state", pt.ID.String(), pt.State)
 	}
 
-	w, err := m.SelectWorker(e.Task)
+	if e.State == task.Completed {
+		if m.cancel(taskID) {
+			slog.Info("Cancelling task waiting for a worker", logging.TaskID, taskID, logging.EventID, e.ID)
+			return nil
+		}
+		// The task is neither running on a worker nor waiting for one, so there
+		// is nothing to stop.
+		slog.Info("Stop requested for task not assigned to a worker", logging.TaskID, taskID, logging.EventID, e.ID)
+		return nil
+	}
+
+	// Resolve the credentials before the task is assigned, so that a task whose
+	// secret is missing fails instead of staying Scheduled on a worker that
+	// never received it.
+	taskEvent := e
+	if e.Task.ImagePullSecret != "" {
+		auth, err := m.Secrets.Get(e.Task.ImagePullSecret)
+		if err != nil {
+			err = fmt.Errorf("failed to resolve image pull secret %s for task %s: %w", e.Task.ImagePullSecret, taskID, err)
+			m.failTask(e.Task, task.ReasonImagePullSecret)
+			return err
+		}
+		taskEvent.RegistryAuth = auth
+	}
+
+	w, err := m.SelectWorker(ctx, e.Task)
 	if err != nil {
-		return fmt.Errorf("failed to select worker for task %s: %w", taskID, err)
+		// Keep the task queued until a worker can take it, so that the
+		// services, cron jobs and workflows waiting for it do not hang.
+		delay := m.backOff(taskID)
+		m.AddTask(e)
+		return fmt.Errorf("failed to select worker for task %s, retrying in %s: %w", taskID, delay, err)
 	}
+	m.clearBackoff(taskID)
 
-	taskEvent := m.Pending.Dequeue().(task.Event)
 	t := taskEvent.Task
 	workerName := w.Name
-	m.TaskWorkerMap[t.ID] = workerName
-	m.WorkerTaskMap[workerName] = append(m.WorkerTaskMap[workerName], t.ID)
+	m.assignTask(t.ID, workerName)
 
 	t.State = task.Scheduled
 	m.TaskStore.Put(t.ID.String(), &t)
+	m.publishTask(&t)
 
-	data, err := json.Marshal(taskEvent)
-	if err != nil {
-		return fmt.Errorf("failed to marshal task event: %w", err)
+	if err := m.sendTaskToWorker(ctx, workerName, taskEvent); err != nil {
+		metrics.SchedulingErrors.Inc()
+		if errors.Is(err, errWorkerUnreachable) {
+			// Schedule the event again, possibly on another worker.
+			m.unassignTask(t.ID)
+			m.AddTask(e)
+		}
+		return err
+	}
+	metrics.SchedulingLatency.Observe(time.Since(taskEvent.Timestamp).Seconds())
+	return nil
+}
+
+// failTask records a task that could not be scheduled as Failed.
+//
+// Parameters:
+//   - t: The task, as it was queued
+//   - reason: Why the task failed, e.g. task.ReasonImagePullSecret
+func (m *Manager) failTask(t task.Task, reason string) {
+	m.clearBackoff(t.ID)
+	slog.Error("Task cannot be scheduled, failing it", logging.TaskID, t.ID, "reason", reason)
+	t.State = task.Failed
+	t.Reason = reason
+	t.EndTime = time.Now().UTC()
+	utils.UpdateStore(m.TaskStore, t.ID.String(), &t)
+	m.publishTask(&t)
+}
+
+// backingOff reports whether a dequeued scheduling event must not be dispatched
+// yet because its task is backing off, requeuing it.
+func (m *Manager) backingOff(e task.Event) bool {
+	if e.State == task.Completed {
+		return false
+	}
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+
+	b, ok := m.unschedulable[e.Task.ID]
+	if !ok || !time.Now().Before(b.next) {
+		return false
 	}
-	return m.sendTaskToWorker(workerName, data)
+	m.enqueue(e)
+	return true
+}
+
+// cancel records that a task whose start event is still queued was stopped.
+//
+// Returns:
+//   - bool: false if no event of the task is queued, so there is nothing to cancel
+func (m *Manager) cancel(id uuid.UUID) bool {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+	if m.pendingTasks[id] == 0 {
+		return false
+	}
+	m.cancelled[id] = true
+	return true
+}
+
+// dropCancelled reports whether a dequeued event starts a task that was stopped
+// while it waited for a worker. Such a task is recorded as Completed instead of
+// being dispatched.
+func (m *Manager) dropCancelled(e task.Event) bool {
+	if e.State == task.Completed {
+		return false
+	}
+	m.pendingMu.Lock()
+	if !m.cancelled[e.Task.ID] {
+		m.pendingMu.Unlock()
+		return false
+	}
+	if m.pendingTasks[e.Task.ID] == 0 {
+		delete(m.cancelled, e.Task.ID)
+		delete(m.unschedulable, e.Task.ID)
+	}
+	m.pendingMu.Unlock()
+
+	slog.Info("Dropping start of task stopped while waiting for a worker", logging.TaskID, e.Task.ID, logging.EventID, e.ID)
+	t := e.Task
+	t.State = task.Completed
+	t.Reason = task.ReasonCancelled
+	t.EndTime = time.Now().UTC()
+	utils.UpdateStore(m.TaskStore, t.ID.String(), &t)
+	m.publishTask(&t)
+	return true
+}
+
+// backOff records a failed attempt to select a worker for a task.
+//
+// Returns:
+//   - time.Duration: How long the task waits before it is scheduled again
+func (m *Manager) backOff(id uuid.UUID) time.Duration {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+
+	b, ok := m.unschedulable[id]
+	if !ok {
+		b = &schedulingBackoff{}
+		m.unschedulable[id] = b
+	}
+	delay := maxSchedulingBackoff
+	if b.attempts < 5 {
+		delay = min(minSchedulingBackoff<<b.attempts, maxSchedulingBackoff)
+	}
+	b.attempts++
+	b.next = time.Now().Add(delay)
+	return delay
+}
+
+// clearBackoff forgets the failed attempts to select a worker for a task.
+func (m *Manager) clearBackoff(id uuid.UUID) {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+	delete(m.unschedulable, id)
+}
+
+// PutSecret stores registry credentials under name so that tasks can reference
+// them through their ImagePullSecret.
+//
+// Parameters:
+//   - name: The name of the secret
+//   - auth: The registry credentials
+//
+// Returns:
+//   - error if the secret store update fails
+func (m *Manager) PutSecret(name string, auth registry.AuthConfig) error {
+	return m.Secrets.Put(name, &auth)
 }
 
 // updateTask updates the manager's task store with the latest task state and metadata
@@ -184,47 +695,164 @@ func (m *Manager) SendWork() error {
 // Returns:
 //   - error if the task store update fails
 func (m *Manager) updateTask(old *task.Task, new *task.Task) error {
-	if old.State != new.State {
-		old.State = new.State
-	}
+	changed := old.State != new.State
 	old.StartTime = new.StartTime
 	old.EndTime = new.EndTime
 	old.State = new.State
 	old.ContainerID = new.ContainerID
-	return m.TaskStore.Put(old.ID.String(), old)
+	old.StopOutcome = new.StopOutcome
+	old.ExitCode = new.ExitCode
+	old.Reason = new.Reason
+	old.Outputs = new.Outputs
+	old.Health = new.Health
+	old.Usage = new.Usage
+	if err := m.TaskStore.Put(old.ID.String(), old); err != nil {
+		return err
+	}
+	if changed {
+		m.publishTask(old)
+	}
+	return nil
 }
 
-// getTasksFromWorker retrieves the current tasks from a worker via HTTP GET request
+// getTasksFromWorker retrieves the current tasks from a worker via HTTP GET
+// requests, following the pages of the worker's task list.
 //
 // Parameters:
+//   - ctx: Context controlling the lifetime of the requests
 //   - workerName: The name/address of the worker to get tasks from
 //
 // Returns:
 //   - []*task.Task: Array of tasks currently running on the worker
-//   - error: If the request fails, worker returns non-200 status, or response cannot be decoded
-func (m *Manager) getTasksFromWorker(workerName string) ([]*task.Task, error) {
-	url := fmt.Sprintf("http://%s/tasks", workerName)
-	resp, err := http.Get(url)
+//   - error: If a request fails, worker returns non-200 status, or response cannot be decoded
+func (m *Manager) getTasksFromWorker(ctx context.Context, workerName string) ([]*task.Task, error) {
+	var tasks []*task.Task
+	q := task.Query{Sort: task.SortByID, Limit: task.MaxPageSize}
+	for {
+		page, next, err := m.getTaskPageFromWorker(ctx, workerName, q)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, page...)
+		if next == "" {
+			return tasks, nil
+		}
+		q.Cursor = next
+	}
+}
+
+// getTaskPageFromWorker retrieves one page of a worker's task list and the
+// cursor of the next page.
+func (m *Manager) getTaskPageFromWorker(ctx context.Context, workerName string, q task.Query) ([]*task.Task, string, error) {
+	url := fmt.Sprintf("http://%s/tasks?%s", workerName, q.Values().Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request to get tasks from worker %s: %w", workerName, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get tasks from worker %s: %w", workerName, err)
+		return nil, "", fmt.Errorf("failed to get tasks from worker %s: %w", workerName, err)
 	}
 
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("error getting tasks from worker %s: %s", workerName, resp.Status)
+		return nil, "", fmt.Errorf("error getting tasks from worker %s: %s", workerName, resp.Status)
 	}
 
 	var tasks []*task.Task
 	err = json.NewDecoder(resp.Body).Decode(&tasks)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode tasks from worker %s: %w", workerName, err)
+		return nil, "", fmt.Errorf("failed to decode tasks from worker %s: %w", workerName, err)
 	}
 
-	return tasks, nil
+	return tasks, resp.Header.Get(handler.NextCursorHeader), nil
 }
 
 func (m *Manager) AddTask(te task.Event) {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+	m.enqueue(te)
+}
+
+// enqueue adds an event to the pending queue. The caller must hold pendingMu.
+func (m *Manager) enqueue(te task.Event) {
 	m.Pending.Enqueue(te)
+	m.pendingTasks[te.Task.ID]++
+	if te.State != task.Completed {
+		m.waiting[te.Task.ID] = te.Task
+	}
+	metrics.PendingTasks.Set(float64(m.Pending.Len()))
+}
+
+// dequeue removes the next event from the pending queue, reporting false if the
+// queue is empty.
+func (m *Manager) dequeue() (task.Event, bool) {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+	defer func() { metrics.PendingTasks.Set(float64(m.Pending.Len())) }()
+
+	if m.Pending.Len() == 0 {
+		return task.Event{}, false
+	}
+	te := m.Pending.Dequeue().(task.Event)
+	if m.pendingTasks[te.Task.ID]--; m.pendingTasks[te.Task.ID] <= 0 {
+		delete(m.pendingTasks, te.Task.ID)
+		delete(m.waiting, te.Task.ID)
+	}
+	return te, true
+}
+
+// workerOf returns the worker a task is assigned to.
+func (m *Manager) workerOf(id uuid.UUID) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	w, ok := m.TaskWorkerMap[id]
+	return w, ok
+}
+
+// assignTask records that a task has been sent to a worker.
+func (m *Manager) assignTask(id uuid.UUID, w string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.TaskWorkerMap[id] = w
+	m.WorkerTaskMap[w] = append(m.WorkerTaskMap[w], id)
+}
+
+// tasksOn returns the IDs of the tasks assigned to a worker.
+func (m *Manager) tasksOn(w string) []uuid.UUID {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Clone(m.WorkerTaskMap[w])
+}
+
+// markSeen records that a worker has just answered a task poll.
+func (m *Manager) markSeen(w string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.WorkerLastSeen[w] = time.Now()
+}
+
+// StopTask queues an event that moves a task to the Completed state, which stops
+// its container on the worker.
+//
+// Parameters:
+//   - t: The task to stop
+//
+// Returns:
+//   - task.Event: The queued stop event
+func (m *Manager) StopTask(t *task.Task) task.Event {
+	taskCopy := *t
+	taskCopy.State = task.Completed
+
+	te := task.Event{
+		ID:        uuid.New(),
+		State:     task.Completed,
+		Timestamp: time.Now(),
+		Task:      taskCopy,
+	}
+	m.AddTask(te)
+	return te
 }
 
 // GetTasks returns a slice of all tasks currently stored in the manager's task store.
@@ -248,61 +876,216 @@ func (m *Manager) GetTasks() ([]*task.Task, error) {
 	return tasks, nil
 }
 
-func (m *Manager) LoopTasks() {
+// ListTasks returns a page of the tasks in the manager's task store.
+//
+// Parameters:
+//   - q: Filters, order and page of the tasks
+//   - worker: If not empty, only tasks assigned to this worker are listed
+//
+// Returns:
+//   - []*task.Task: The tasks of the page
+//   - string: The cursor of the next page; empty if this is the last one
+//   - int: The number of matching tasks across all pages
+//   - error: If the task store cannot be read or the cursor is malformed
+func (m *Manager) ListTasks(q task.Query, worker string) ([]*task.Task, string, int, error) {
+	tasks, err := m.GetTasks()
+	if err != nil {
+		return nil, "", 0, err
+	}
+	if worker != "" {
+		tasks = slices.DeleteFunc(tasks, func(t *task.Task) bool {
+			w, _ := m.workerOf(t.ID)
+			return w != worker
+		})
+	}
+	return q.Page(tasks)
+}
+
+// TaskDetails is a task together with the worker it is assigned to.
+type TaskDetails struct {
+	task.Task
+	// Worker is the address of the worker running the task; empty if it is not
+	// assigned to one.
+	Worker string `json:",omitempty"`
+	// SchedulingAttempts is the number of failed attempts to select a worker for
+	// a task waiting for one.
+	SchedulingAttempts int `json:",omitempty"`
+	// NextAttempt is when a task backing off after such failures is scheduled again.
+	NextAttempt *time.Time `json:",omitempty"`
+}
+
+// GetTask returns a task that has been scheduled on a worker, or one that is
+// still waiting for a worker in the pending queue, possibly backing off.
+//
+// Parameters:
+//   - id: The ID of the task
+//
+// Returns:
+//   - *TaskDetails: The task and the worker it is assigned to
+//   - error: Wrapping store.ErrNotFound if the task is unknown
+func (m *Manager) GetTask(id uuid.UUID) (*TaskDetails, error) {
+	t, err := m.TaskStore.Get(id.String())
+	if errors.Is(err, store.ErrNotFound) {
+		if d, ok := m.waitingTask(id); ok {
+			return d, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	w, _ := m.workerOf(id)
+	return &TaskDetails{Task: *t, Worker: w}, nil
+}
+
+// waitingTask returns a task whose start event is in the pending queue, with its
+// scheduling backoff if no worker could be selected for it.
+func (m *Manager) waitingTask(id uuid.UUID) (*TaskDetails, bool) {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+
+	t, ok := m.waiting[id]
+	if !ok {
+		return nil, false
+	}
+	t.State = task.Pending
+	d := &TaskDetails{Task: t}
+	if b, ok := m.unschedulable[id]; ok {
+		next := b.next
+		d.SchedulingAttempts = b.attempts
+		d.NextAttempt = &next
+	}
+	return d, true
+}
+
+// TaskEvents returns the events of a task that have been dispatched, oldest first.
+//
+// Parameters:
+//   - id: The ID of the task
+//
+// Returns:
+//   - []*task.Event: The events of the task ordered by timestamp; empty if there are none
+//   - error: If the event store cannot be read
+func (m *Manager) TaskEvents(id uuid.UUID) ([]*task.Event, error) {
+	all, err := m.EventStore.List()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*task.Event, 0)
+	for _, e := range all {
+		if e.Task.ID == id {
+			events = append(events, e)
+		}
+	}
+	slices.SortStableFunc(events, func(a, b *task.Event) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	return events, nil
+}
+
+// LoopTasks sends the pending tasks to workers every 10 seconds until ctx is cancelled.
+// Each round sends the events that were pending when it started, so events put
+// back on the queue after a failed dispatch wait for the next round.
+//
+// This function should be started in a separate goroutine.
+func (m *Manager) LoopTasks(ctx context.Context) {
 	for {
-		log.Println("Processing any tasks in the queue")
-		err := m.SendWork()
+		m.pendingMu.Lock()
+		n := m.Pending.Len()
+		m.pendingMu.Unlock()
 
-		if err != nil {
-			err = fmt.Errorf("error processing tasks: %w", err)
-			log.Println(err)
+		if n == 0 {
+			slog.Debug("No pending tasks")
+		}
+		for range n {
+			if err := m.SendWork(ctx); err != nil && !errors.Is(err, ErrNoPendingTasks) {
+				slog.Error("Error processing tasks", "error", err)
+			}
 		}
 
-		log.Println("Sleeping for 10 seconds")
-		time.Sleep(10 * time.Second)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(10 * time.Second):
+		}
 	}
 }
 
 // stopTask sends a request to stop a specific task on a worker node
 //
 // Parameters:
+//   - ctx: Context controlling the lifetime of the request
 //   - workerName: The name/address of the worker running the task
 //   - taskID: The ID of the task to stop
 //
 // Returns:
 //   - error: If the request fails, worker returns non-204 status, or other errors occur
-func (m *Manager) stopTask(workerName string, taskID string) error {
-	var httpClient http.Client
+func (m *Manager) stopTask(ctx context.Context, workerName string, taskID string) error {
 	url := fmt.Sprintf("http://%s/tasks/%s", workerName, taskID)
 
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request to stop task %s on worker %s: %w", taskID, workerName, err)
 	}
 
-	resp, err := httpClient.Do(req)
+	resp, err := workerClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to stop task %s on worker %s: %w", taskID, workerName, err)
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
 		return fmt.Errorf("failed to stop task %s on worker %s: %s", taskID, workerName, resp.Status)
 	}
 
-	log.Printf("Task %s stopped on worker %s", taskID, workerName)
+	slog.Info("Task stopped on worker", logging.TaskID, taskID, logging.Worker, workerName)
+	return nil
+}
+
+// removeTask sends a request to remove a finished task, its container and its
+// data from a worker node
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the request
+//   - workerName: The name/address of the worker the task ran on
+//   - taskID: The ID of the task to remove
+//
+// Returns:
+//   - error: If the request fails or the worker returns a status other than 204,
+//     or 404 for a task it no longer knows
+func (m *Manager) removeTask(ctx context.Context, workerName string, taskID string) error {
+	url := fmt.Sprintf("http://%s/tasks/%s/record", workerName, taskID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request to remove task %s on worker %s: %w", taskID, workerName, err)
+	}
+
+	resp, err := workerClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to remove task %s on worker %s: %w", taskID, workerName, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to remove task %s on worker %s: %s", taskID, workerName, resp.Status)
+	}
+
+	slog.Info("Task removed from worker", logging.TaskID, taskID, logging.Worker, workerName)
 	return nil
 }
 
 // restartTask attempts to restart a task on its assigned worker
 //
 // Parameters:
+//   - ctx: Context controlling the lifetime of the request to the worker
 //   - t: The task to restart
 //
 // Returns:
 //   - error: If the task is not found in the worker map, task state update fails,
 //     event marshaling fails, or sending to worker fails
-func (m *Manager) restartTask(t *task.Task) error {
-	w, ok := m.TaskWorkerMap[t.ID]
+func (m *Manager) restartTask(ctx context.Context, t *task.Task) error {
+	w, ok := m.workerOf(t.ID)
 	if !ok {
 		return fmt.Errorf("task %s not found", t.ID)
 	}
@@ -312,6 +1095,7 @@ func (m *Manager) restartTask(t *task.Task) error {
 	if err != nil {
 		return fmt.Errorf("failed to update task %s: %w", t.ID, err)
 	}
+	m.publishTask(t)
 
 	te := task.Event{
 		ID:        uuid.New(),
@@ -319,39 +1103,53 @@ func (m *Manager) restartTask(t *task.Task) error {
 		Timestamp: time.Now(),
 		Task:      *t,
 	}
-	data, err := json.Marshal(te)
-	if err != nil {
-		return fmt.Errorf("failed to marshal task event: %w", err)
-	}
-	return m.sendTaskToWorker(w, data)
+	return m.sendTaskToWorker(ctx, w, te)
 }
 
 // sendTaskToWorker sends a task to a worker via HTTP POST request
 //
 // Parameters:
+//   - ctx: Context controlling the lifetime of the request
 //   - workerName: The name/address of the worker to send the task to
-//   - data: JSON encoded task event data to send
+//   - te: The task event to send
 //
 // Returns:
 //   - error if the request fails, the worker returns an error response,
-//     or the response cannot be decoded
-func (m *Manager) sendTaskToWorker(workerName string, data []byte) error {
+//     or the response cannot be decoded; errWorkerUnreachable if the worker
+//     could not be reached at all
+func (m *Manager) sendTaskToWorker(ctx context.Context, workerName string, te task.Event) (err error) {
+	ctx, span := tracer.Start(ctx, "sendTaskToWorker", trace.WithAttributes(attribute.String("worker", workerName)))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+	}()
+
+	data, err := json.Marshal(te)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task event: %w", err)
+	}
+
 	url := fmt.Sprintf("http://%s/tasks", workerName)
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(data))
 	if err != nil {
-		m.Pending.Enqueue(data)
-		return fmt.Errorf("failed to send task to worker %s: %w", workerName, err)
+		return fmt.Errorf("failed to create request to send task to worker %s: %w", workerName, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := workerClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send task to worker %s: %w: %w", workerName, errWorkerUnreachable, err)
 	}
 
 	defer resp.Body.Close()
 	decoder := json.NewDecoder(resp.Body)
-	if resp.StatusCode != http.StatusCreated {
-		var errResp worker.ErrorResponse
+	if resp.StatusCode != http.StatusOK {
+		var errResp handler.ResponseError
 		err := decoder.Decode(&errResp)
 		if err != nil {
 			return fmt.Errorf("failed to decode error response: %w", err)
 		}
-		return fmt.Errorf("failed to send task to worker %s: %s", workerName, resp.Status)
+		return fmt.Errorf("failed to send task to worker %s: %s: %s", workerName, resp.Status, errResp.Message)
 	}
 
 	var t task.Task
@@ -360,6 +1158,6 @@ func (m *Manager) sendTaskToWorker(workerName string, data []byte) error {
 		return fmt.Errorf("failed to decode task response: %w", err)
 	}
 
-	log.Printf("Task %s sent to worker %s", t.ID, workerName)
+	slog.Info("Task sent to worker", logging.TaskID, t.ID, logging.Worker, workerName)
 	return nil
 }
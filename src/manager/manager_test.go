@@ -0,0 +1,345 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/cronjob"
+	"github.com/utkarsh5026/Orchestra/flow"
+	"github.com/utkarsh5026/Orchestra/scheduler"
+	"github.com/utkarsh5026/Orchestra/store"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// fakeWorker records the task events, stops and removals sent to it.
+type fakeWorker struct {
+	mu      sync.Mutex
+	started []uuid.UUID
+	stopped []uuid.UUID
+	removed []uuid.UUID
+}
+
+func (f *fakeWorker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPost:
+		var te task.Event
+		if err := json.NewDecoder(r.Body).Decode(&te); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.started = append(f.started, te.Task.ID)
+		json.NewEncoder(w).Encode(te.Task)
+	case http.MethodDelete:
+		path, remove := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/tasks/"), "/record")
+		id, err := uuid.Parse(path)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if remove {
+			f.removed = append(f.removed, id)
+		} else {
+			f.stopped = append(f.stopped, id)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeWorker) counts() (started, stopped int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.started), len(f.stopped)
+}
+
+// newTestManager returns a manager scheduling onto the given worker addresses.
+func newTestManager(workers ...string) *Manager {
+	return NewManager(workers, scheduler.RoundRobinScheduler, store.InMemoryStoreType)
+}
+
+func startEvent() task.Event {
+	return task.Event{
+		ID:        uuid.New(),
+		State:     task.Scheduled,
+		Timestamp: time.Now(),
+		Task:      task.Task{ID: uuid.New(), Name: "test", Image: "nginx:1.27", State: task.Pending},
+	}
+}
+
+// sendAll calls SendWork until the pending queue is empty, at most n times.
+func sendAll(t *testing.T, m *Manager, n int) {
+	t.Helper()
+	for range n {
+		m.pendingMu.Lock()
+		empty := m.Pending.Len() == 0
+		m.pendingMu.Unlock()
+		if empty {
+			return
+		}
+		_ = m.SendWork(context.Background())
+	}
+	t.Fatalf("pending queue not empty after %d dispatches", n)
+}
+
+func TestSendWorkBacksOffUnschedulableTask(t *testing.T) {
+	m := newTestManager()
+	e := startEvent()
+	m.AddTask(e)
+
+	err := m.SendWork(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "retrying in 10s") {
+		t.Fatalf("SendWork() error = %v, want a retry in 10s", err)
+	}
+	if !m.hasTask(e.Task.ID) {
+		t.Fatal("unschedulable task was dropped from the pending queue")
+	}
+
+	// The task is requeued without another scheduling attempt until its backoff expires.
+	if err := m.SendWork(context.Background()); err != nil {
+		t.Fatalf("SendWork() during backoff error = %v, want nil", err)
+	}
+	if got := m.unschedulable[e.Task.ID].attempts; got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+
+	m.unschedulable[e.Task.ID].next = time.Now()
+	err = m.SendWork(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "retrying in 20s") {
+		t.Fatalf("SendWork() after backoff error = %v, want a retry in 20s", err)
+	}
+}
+
+func TestStopTaskWaitingForWorker(t *testing.T) {
+	tests := []struct {
+		name string
+		// workers returns the worker addresses of the manager.
+		workers func(t *testing.T) []string
+	}{
+		{
+			name:    "backing off",
+			workers: func(t *testing.T) []string { return nil },
+		},
+		{
+			name: "requeued after unreachable worker",
+			workers: func(t *testing.T) []string {
+				srv := httptest.NewServer(http.NotFoundHandler())
+				srv.Close()
+				return []string{srv.Listener.Addr().String()}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager(tt.workers(t)...)
+			e := startEvent()
+			m.AddTask(e)
+			if err := m.SendWork(context.Background()); err == nil {
+				t.Fatal("SendWork() error = nil, want the dispatch to fail")
+			}
+
+			m.StopTask(&e.Task)
+			sendAll(t, m, 5)
+
+			got, err := m.TaskStore.Get(e.Task.ID.String())
+			if err != nil {
+				t.Fatalf("cancelled task not recorded: %v", err)
+			}
+			if got.State != task.Completed || got.Reason != task.ReasonCancelled {
+				t.Errorf("task is %s (%q), want Completed (%q)", got.State, got.Reason, task.ReasonCancelled)
+			}
+			if _, ok := m.workerOf(e.Task.ID); ok {
+				t.Error("cancelled task is assigned to a worker")
+			}
+			if len(m.cancelled) != 0 || len(m.unschedulable) != 0 {
+				t.Errorf("cancelled = %v, unschedulable = %v, want both empty", m.cancelled, m.unschedulable)
+			}
+		})
+	}
+}
+
+func TestStopScheduledTaskStopsItOnWorker(t *testing.T) {
+	fw := &fakeWorker{}
+	srv := httptest.NewServer(fw)
+	defer srv.Close()
+
+	m := newTestManager(srv.Listener.Addr().String())
+	e := startEvent()
+	m.AddTask(e)
+	if err := m.SendWork(context.Background()); err != nil {
+		t.Fatalf("SendWork() error = %v", err)
+	}
+
+	// The worker has not reported the task as Running yet.
+	scheduled, err := m.TaskStore.Get(e.Task.ID.String())
+	if err != nil || scheduled.State != task.Scheduled {
+		t.Fatalf("task = %v, %v, want it Scheduled", scheduled, err)
+	}
+
+	m.StopTask(scheduled)
+	if err := m.SendWork(context.Background()); err != nil {
+		t.Fatalf("SendWork() of the stop error = %v", err)
+	}
+	if started, stopped := fw.counts(); started != 1 || stopped != 1 {
+		t.Errorf("worker got %d starts and %d stops, want 1 and 1", started, stopped)
+	}
+}
+
+func TestStopUnknownTaskIsIgnored(t *testing.T) {
+	m := newTestManager()
+	m.StopTask(&task.Task{ID: uuid.New()})
+
+	if err := m.SendWork(context.Background()); err != nil {
+		t.Fatalf("SendWork() error = %v, want nil", err)
+	}
+	if len(m.cancelled) != 0 {
+		t.Errorf("cancelled = %v, want it empty", m.cancelled)
+	}
+}
+
+// expiredJob stores a job with the given ID that finished long enough ago for
+// its TTL to have expired.
+func expiredJob(t *testing.T, m *Manager, id uuid.UUID) *task.Task {
+	t.Helper()
+	j := &task.Task{
+		ID:               id,
+		Kind:             task.KindJob,
+		State:            task.Completed,
+		TTLAfterFinished: time.Second,
+		EndTime:          time.Now().UTC().Add(-time.Minute),
+	}
+	if err := m.TaskStore.Put(j.ID.String(), j); err != nil {
+		t.Fatalf("storing job: %v", err)
+	}
+	return j
+}
+
+func TestCleanupExpiredJobsKeepsActiveCronRuns(t *testing.T) {
+	m := newTestManager()
+	cj, err := cronjob.New(cronjob.CronJob{Name: "nightly", Schedule: "0 0 * * *", Template: task.Task{Image: "busybox"}})
+	if err != nil {
+		t.Fatalf("cronjob.New() error = %v", err)
+	}
+	run := expiredJob(t, m, uuid.New())
+	cj.Active = []uuid.UUID{run.ID}
+	m.CronJobs.Put(cj.ID.String(), cj)
+	unowned := expiredJob(t, m, uuid.New())
+
+	m.cleanupExpiredJobs()
+	if _, err := m.TaskStore.Get(run.ID.String()); err != nil {
+		t.Errorf("active run of cron job was cleaned up: %v", err)
+	}
+	if _, err := m.TaskStore.Get(unowned.ID.String()); err == nil {
+		t.Error("expired job without owner was kept")
+	}
+
+	m.evaluateCronJob(context.Background(), cj, cj.CreatedAt)
+	if len(cj.Active) != 0 {
+		t.Errorf("Active = %v after the run finished, want it empty", cj.Active)
+	}
+	m.cleanupExpiredJobs()
+	if _, err := m.TaskStore.Get(run.ID.String()); err == nil {
+		t.Error("finished run of cron job was kept after its TTL expired")
+	}
+}
+
+func TestCleanupExpiredJobsKeepsTasksOfUnfinishedWorkflows(t *testing.T) {
+	m := newTestManager()
+	wf, err := flow.New("build", []*flow.Step{{Name: "compile", Task: task.Task{Image: "golang:1.23"}}})
+	if err != nil {
+		t.Fatalf("flow.New() error = %v", err)
+	}
+	m.Workflows.Put(wf.ID.String(), wf)
+	m.updateWorkflow(wf)
+	step := wf.Step("compile")
+	expiredJob(t, m, step.TaskID)
+
+	m.cleanupExpiredJobs()
+	if _, err := m.TaskStore.Get(step.TaskID.String()); err != nil {
+		t.Fatalf("task of running step was cleaned up: %v", err)
+	}
+
+	m.updateWorkflow(wf)
+	if step.State != flow.Completed || !wf.IsFinished() {
+		t.Fatalf("step is %v and workflow is %v, want both Completed", step.State, wf.State)
+	}
+	m.cleanupExpiredJobs()
+	if _, err := m.TaskStore.Get(step.TaskID.String()); err == nil {
+		t.Error("task of finished workflow was kept after its TTL expired")
+	}
+}
+
+func TestEvaluateCronJobRemovesEvictedRunsFromWorker(t *testing.T) {
+	fw := &fakeWorker{}
+	srv := httptest.NewServer(fw)
+	defer srv.Close()
+	addr := srv.Listener.Addr().String()
+
+	m := newTestManager(addr)
+	cj, err := cronjob.New(cronjob.CronJob{Name: "nightly", Schedule: "0 0 * * *", HistoryLimit: 1, Template: task.Task{Image: "busybox"}})
+	if err != nil {
+		t.Fatalf("cronjob.New() error = %v", err)
+	}
+	oldest := expiredJob(t, m, uuid.New())
+	m.assignTask(oldest.ID, addr)
+	cj.History = []uuid.UUID{oldest.ID}
+	run := expiredJob(t, m, uuid.New())
+	m.assignTask(run.ID, addr)
+	cj.Active = []uuid.UUID{run.ID}
+	m.CronJobs.Put(cj.ID.String(), cj)
+
+	m.evaluateCronJob(context.Background(), cj, cj.CreatedAt)
+	fw.mu.Lock()
+	removed := slices.Clone(fw.removed)
+	fw.mu.Unlock()
+	if !slices.Equal(removed, []uuid.UUID{oldest.ID}) {
+		t.Errorf("worker removed %v, want [%v]", removed, oldest.ID)
+	}
+	if _, err := m.TaskStore.Get(oldest.ID.String()); err == nil {
+		t.Error("evicted run of cron job was kept")
+	}
+	if _, ok := m.workerOf(oldest.ID); ok {
+		t.Error("evicted run of cron job is still assigned to its worker")
+	}
+	if !slices.Equal(cj.History, []uuid.UUID{run.ID}) {
+		t.Errorf("History = %v, want [%v]", cj.History, run.ID)
+	}
+}
+
+func TestEvaluateCronJobKeepsRunsItCannotRemove(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	addr := srv.Listener.Addr().String()
+
+	m := newTestManager(addr)
+	cj, err := cronjob.New(cronjob.CronJob{Name: "nightly", Schedule: "0 0 * * *", HistoryLimit: 1, Template: task.Task{Image: "busybox"}})
+	if err != nil {
+		t.Fatalf("cronjob.New() error = %v", err)
+	}
+	oldest := expiredJob(t, m, uuid.New())
+	m.assignTask(oldest.ID, addr)
+	cj.History = []uuid.UUID{oldest.ID}
+	run := expiredJob(t, m, uuid.New())
+	cj.Active = []uuid.UUID{run.ID}
+	m.CronJobs.Put(cj.ID.String(), cj)
+
+	m.evaluateCronJob(context.Background(), cj, cj.CreatedAt)
+	if _, err := m.TaskStore.Get(oldest.ID.String()); err != nil {
+		t.Errorf("run that could not be removed from its worker was forgotten: %v", err)
+	}
+	if !slices.Equal(cj.History, []uuid.UUID{oldest.ID, run.ID}) {
+		t.Errorf("History = %v, want [%v %v]", cj.History, oldest.ID, run.ID)
+	}
+}
@@ -0,0 +1,40 @@
+package manager
+
+import (
+	"time"
+)
+
+// NodeStatus describes a worker node as seen by the manager.
+type NodeStatus struct {
+	Name string
+	Api  string
+	Role string
+	// Lost is true once the worker has not answered a task poll for WorkerTimeout.
+	Lost bool
+	// LastSeen is when the worker last answered a task poll.
+	LastSeen time.Time
+	// Tasks is the number of tasks assigned to the worker.
+	Tasks int
+}
+
+// Nodes returns the status of every worker node, in the order the workers were
+// given to the manager.
+func (m *Manager) Nodes() []NodeStatus {
+	nodes := make([]NodeStatus, 0, len(m.WorkerNodes))
+	for _, n := range m.WorkerNodes {
+		m.mu.RLock()
+		lastSeen := m.WorkerLastSeen[n.Name]
+		tasks := len(m.WorkerTaskMap[n.Name])
+		m.mu.RUnlock()
+
+		nodes = append(nodes, NodeStatus{
+			Name:     n.Name,
+			Api:      n.Api,
+			Role:     n.Role,
+			Lost:     time.Since(lastSeen) > m.WorkerTimeout,
+			LastSeen: lastSeen,
+			Tasks:    tasks,
+		})
+	}
+	return nodes
+}
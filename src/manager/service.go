@@ -0,0 +1,353 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/logging"
+	"github.com/utkarsh5026/Orchestra/manifest"
+	"github.com/utkarsh5026/Orchestra/service"
+	"github.com/utkarsh5026/Orchestra/task"
+	"github.com/utkarsh5026/Orchestra/utils"
+)
+
+// AddService admits the template of a service, creates the service and starts
+// its replicas.
+//
+// Parameters:
+//   - ctx: Context bounding the calls of the admission webhooks
+//   - req: The service to create, as passed to service.New
+//
+// Returns:
+//   - *service.Service: The created service
+//   - error: Wrapping ErrInvalidObject if the service is invalid, an
+//     *AdmissionError if a webhook rejected its template, or the error of the
+//     service store update
+func (m *Manager) AddService(ctx context.Context, req service.Service) (*service.Service, error) {
+	key := manifest.Key(manifest.KindService, req.Name)
+	if err := m.AdmitTemplate(ctx, &req.Template, key, "Template."); err != nil {
+		return nil, err
+	}
+	svc, err := service.New(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidObject, err)
+	}
+
+	m.servicesMu.Lock()
+	defer m.servicesMu.Unlock()
+
+	if err := m.Services.Put(svc.ID.String(), svc); err != nil {
+		return nil, fmt.Errorf("failed to store service %s: %w", svc.ID, err)
+	}
+	m.reconcileService(svc)
+	return svc, nil
+}
+
+// ScaleService changes the desired replica count of a service and converges to it.
+//
+// Parameters:
+//   - id: The ID of the service
+//   - replicas: The desired number of replicas
+//
+// Returns:
+//   - *service.Service: The updated service
+//   - error if the service does not exist or the replica count is negative or
+//     outside the autoscaling bounds of the service
+func (m *Manager) ScaleService(id uuid.UUID, replicas int) (*service.Service, error) {
+	if replicas < 0 {
+		return nil, fmt.Errorf("invalid replica count %d", replicas)
+	}
+
+	m.servicesMu.Lock()
+	defer m.servicesMu.Unlock()
+
+	svc, err := m.Services.Get(id.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if as := svc.Autoscaling; as != nil && (replicas < as.MinReplicas || replicas > as.MaxReplicas) {
+		return nil, fmt.Errorf("replica count %d outside autoscaling bounds %d..%d", replicas, as.MinReplicas, as.MaxReplicas)
+	}
+
+	svc.Replicas = replicas
+	m.reconcileService(svc)
+	return svc, nil
+}
+
+// UpdateService admits template and makes it the current template of a service
+// as a new revision, then starts a rolling update to it.
+//
+// Parameters:
+//   - ctx: Context bounding the calls of the admission webhooks
+//   - id: The ID of the service
+//   - template: The new task template
+//   - strategy: The rolling update strategy, or nil to keep the current one
+//
+// Returns:
+//   - *service.Service: The updated service
+//   - error: The store error if the service does not exist, wrapping
+//     ErrInvalidObject if the template or strategy is invalid, or an
+//     *AdmissionError if a webhook rejected the template
+func (m *Manager) UpdateService(ctx context.Context, id uuid.UUID, template task.Task, strategy *service.Strategy) (*service.Service, error) {
+	svc, err := m.Services.Get(id.String())
+	if err != nil {
+		return nil, err
+	}
+	// The name of a service never changes, so it can be read without servicesMu.
+	key := manifest.Key(manifest.KindService, svc.Name)
+	if err := m.AdmitTemplate(ctx, &template, key, "Template."); err != nil {
+		return nil, err
+	}
+
+	m.servicesMu.Lock()
+	defer m.servicesMu.Unlock()
+
+	svc, err = m.Services.Get(id.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if strategy != nil {
+		if err := strategy.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidObject, err)
+		}
+		svc.Strategy = *strategy
+	}
+	if err := svc.Update(template); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidObject, err)
+	}
+
+	m.reconcileService(svc)
+	return svc, nil
+}
+
+// RollbackService rolls a service back to the template of an earlier revision.
+//
+// Parameters:
+//   - id: The ID of the service
+//   - revision: The revision to restore; zero means the one before the current one
+//
+// Returns:
+//   - *service.Service: The updated service
+//   - error if the service does not exist or the revision is not in its history
+func (m *Manager) RollbackService(id uuid.UUID, revision int) (*service.Service, error) {
+	m.servicesMu.Lock()
+	defer m.servicesMu.Unlock()
+
+	svc, err := m.Services.Get(id.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := svc.Rollback(revision); err != nil {
+		return nil, err
+	}
+
+	m.reconcileService(svc)
+	return svc, nil
+}
+
+// ResumeService lets the paused rollout of a service progress again.
+//
+// Parameters:
+//   - id: The ID of the service
+//
+// Returns:
+//   - *service.Service: The updated service
+//   - error if the service does not exist
+func (m *Manager) ResumeService(id uuid.UUID) (*service.Service, error) {
+	m.servicesMu.Lock()
+	defer m.servicesMu.Unlock()
+
+	svc, err := m.Services.Get(id.String())
+	if err != nil {
+		return nil, err
+	}
+
+	svc.Resume()
+	m.reconcileService(svc)
+	return svc, nil
+}
+
+// DeleteService stops all tasks of a service and removes it.
+//
+// Parameters:
+//   - id: The ID of the service
+//
+// Returns:
+//   - error if the service does not exist or cannot be deleted
+func (m *Manager) DeleteService(id uuid.UUID) error {
+	m.servicesMu.Lock()
+	defer m.servicesMu.Unlock()
+
+	svc, err := m.Services.Get(id.String())
+	if err != nil {
+		return err
+	}
+
+	for _, r := range slices.Clone(svc.Tasks) {
+		m.stopServiceTask(svc, r.TaskID)
+	}
+	return m.Services.Delete(id.String())
+}
+
+// ReconcileServices periodically converges every service to its desired replica count.
+//
+// Parameters:
+//   - ctx: Context whose cancellation stops the loop
+//   - d: The duration to wait between reconciliations
+//
+// This function runs until ctx is cancelled and should be started in a separate goroutine.
+func (m *Manager) ReconcileServices(ctx context.Context, d time.Duration) {
+	for {
+		m.reconcileServices()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d):
+		}
+	}
+}
+
+func (m *Manager) reconcileServices() {
+	svcs, err := m.Services.List()
+	if err != nil {
+		slog.Error("Error listing services", "error", err)
+		return
+	}
+
+	for _, svc := range svcs {
+		m.servicesMu.Lock()
+		if m.serviceExists(svc) {
+			m.reconcileService(svc)
+		}
+		m.servicesMu.Unlock()
+	}
+}
+
+// serviceExists reports whether svc is still stored, i.e. it was not deleted
+// since it was listed. The caller must hold servicesMu.
+func (m *Manager) serviceExists(svc *service.Service) bool {
+	cur, err := m.Services.Get(svc.ID.String())
+	return err == nil && cur == svc
+}
+
+// reconcileService drops finished tasks from a service, including tasks failed
+// because their worker was lost. While replicas of older revisions remain it
+// performs a rolling update step; otherwise it starts or stops tasks until the
+// number of tasks matches the desired replica count. The caller must hold
+// servicesMu.
+func (m *Manager) reconcileService(svc *service.Service) {
+	tasks := make(map[uuid.UUID]*task.Task, len(svc.Tasks))
+	for _, r := range slices.Clone(svc.Tasks) {
+		t, err := m.TaskStore.Get(r.TaskID.String())
+		if err != nil {
+			// The task has not been scheduled on a worker yet.
+			continue
+		}
+		if !t.IsFinished() {
+			tasks[r.TaskID] = t
+			continue
+		}
+
+		slog.Info("Task of service finished", logging.Service, svc.Name, logging.TaskID, r.TaskID, "state", t.State)
+		svc.Remove(r.TaskID)
+		if t.State == task.Failed && r.Revision == svc.Revision && svc.Rollout.State == service.RolloutProgressing {
+			svc.Pause(fmt.Sprintf("task %s of revision %d failed", r.TaskID, r.Revision))
+			slog.Warn("Paused rollout of service", logging.Service, svc.Name, "reason", svc.Rollout.Reason)
+		}
+	}
+
+	if svc.Rollout.State != service.RolloutPaused {
+		current, old := svc.Split()
+		if len(old) > 0 {
+			m.rollService(svc, tasks, current, old)
+		} else {
+			m.scaleService(svc)
+			if svc.Rollout.State == service.RolloutProgressing && countReady(tasks, current) == svc.Replicas {
+				svc.Rollout = service.Rollout{State: service.RolloutComplete}
+				slog.Info("Rollout of service complete", logging.Service, svc.Name, "revision", svc.Revision)
+			}
+		}
+	}
+
+	utils.UpdateStore(m.Services, svc.ID.String(), svc)
+}
+
+// scaleService starts or stops tasks of the current revision until the number of
+// tasks matches the desired replica count.
+func (m *Manager) scaleService(svc *service.Service) {
+	for len(svc.Tasks) < svc.Replicas {
+		m.startServiceTask(svc)
+	}
+
+	for len(svc.Tasks) > svc.Replicas {
+		m.stopServiceTask(svc, svc.Tasks[len(svc.Tasks)-1].TaskID)
+	}
+}
+
+// rollService performs one step of a rolling update. It starts replicas of the
+// current revision while the total stays within Replicas+MaxSurge, and stops
+// replicas of older revisions while at least Replicas-MaxUnavailable replicas
+// stay ready. New replicas only count as ready once they pass their health check,
+// so each batch waits for the previous one.
+func (m *Manager) rollService(svc *service.Service, tasks map[uuid.UUID]*task.Task, current, old []service.Replica) {
+	surge, unavailable := svc.Strategy.Limits()
+
+	for len(current) < svc.Replicas && len(current)+len(old) < svc.Replicas+surge {
+		m.startServiceTask(svc)
+		current = append(current, svc.Tasks[len(svc.Tasks)-1])
+	}
+
+	ready := countReady(tasks, current) + countReady(tasks, old)
+	minAvailable := svc.Replicas - unavailable
+	for _, r := range old {
+		if t, ok := tasks[r.TaskID]; ok && t.IsReady() {
+			if ready <= minAvailable {
+				continue
+			}
+			ready--
+		}
+		m.stopServiceTask(svc, r.TaskID)
+	}
+}
+
+func (m *Manager) startServiceTask(svc *service.Service) {
+	t := svc.NewTask()
+	svc.Add(t.ID)
+	slog.Info("Starting task for service", logging.Service, svc.Name, logging.TaskID, t.ID, "revision", svc.Revision)
+	m.AddTask(task.Event{
+		ID:        uuid.New(),
+		State:     task.Scheduled,
+		Timestamp: time.Now(),
+		Task:      t,
+	})
+}
+
+func (m *Manager) stopServiceTask(svc *service.Service, id uuid.UUID) {
+	svc.Remove(id)
+
+	t, err := m.TaskStore.Get(id.String())
+	if err != nil {
+		// The task is still waiting for a worker; stop it from its ID.
+		t = &task.Task{ID: id}
+	}
+	slog.Info("Stopping task of service", logging.Service, svc.Name, logging.TaskID, id)
+	m.StopTask(t)
+}
+
+// countReady returns how many of the replicas are running and healthy.
+func countReady(tasks map[uuid.UUID]*task.Task, replicas []service.Replica) int {
+	n := 0
+	for _, r := range replicas {
+		if t, ok := tasks[r.TaskID]; ok && t.IsReady() {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,92 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/logging"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// ErrNoStats is returned when no usage has been recorded for a task.
+var ErrNoStats = errors.New("no stats recorded for task")
+
+// GetTaskStats fetches the usage history of a task from the worker running it.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the request
+//   - id: The ID of the task
+//
+// Returns:
+//   - task.Stats: The usage history, with Worker set
+//   - error: ErrNoStats if the task is not assigned to a worker or the worker has
+//     no usage for it, or an error if the worker cannot be reached
+func (m *Manager) GetTaskStats(ctx context.Context, id uuid.UUID) (task.Stats, error) {
+	w, ok := m.workerOf(id)
+	if !ok {
+		return task.Stats{}, ErrNoStats
+	}
+
+	var stats task.Stats
+	if err := m.getStatsFromWorker(ctx, w, fmt.Sprintf("/tasks/%s/stats", id), &stats); err != nil {
+		return task.Stats{}, err
+	}
+	stats.Worker = w
+	return stats, nil
+}
+
+// GetAllStats collects the usage history of every sampled task from all workers.
+// Workers that cannot be reached are logged and skipped.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the requests
+//
+// Returns:
+//   - []task.Stats: The usage histories, each with Worker set
+func (m *Manager) GetAllStats(ctx context.Context) []task.Stats {
+	all := make([]task.Stats, 0)
+	for _, w := range m.Workers {
+		var stats []task.Stats
+		if err := m.getStatsFromWorker(ctx, w, "/tasks/stats", &stats); err != nil {
+			slog.Error("Error getting stats from worker", logging.Worker, w, "error", err)
+			continue
+		}
+		for i := range stats {
+			stats[i].Worker = w
+		}
+		all = append(all, stats...)
+	}
+	return all
+}
+
+func (m *Manager) getStatsFromWorker(ctx context.Context, workerName string, path string, v any) error {
+	url := fmt.Sprintf("http://%s%s", workerName, path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request to get stats from worker %s: %w", workerName, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to get stats from worker %s: %w", workerName, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return ErrNoStats
+	default:
+		return fmt.Errorf("error getting stats from worker %s: %s", workerName, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode stats from worker %s: %w", workerName, err)
+	}
+	return nil
+}
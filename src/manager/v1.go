@@ -0,0 +1,287 @@
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	v1 "github.com/utkarsh5026/Orchestra/api/v1"
+	"github.com/utkarsh5026/Orchestra/handler"
+	"github.com/utkarsh5026/Orchestra/logging"
+	"github.com/utkarsh5026/Orchestra/store"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// v1Routes mounts version 1 of the API. Its requests and responses are the
+// types of package v1, and requests are validated against the OpenAPI document
+// served at /openapi.json before they are handled.
+func (a *Api) v1Routes(r chi.Router) {
+	r.Route("/tasks", func(r chi.Router) {
+		r.Post("/", a.CreateTaskV1Handler)
+		r.Get("/", a.ListTasksV1Handler)
+		r.Get("/watch", a.WatchTasksV1Handler)
+		r.Get("/{id}", a.GetTaskV1Handler)
+		r.Delete("/{id}", a.StopTaskV1Handler)
+		r.Get("/{id}/events", a.ListTaskEventsV1Handler)
+		r.Get("/{id}/logs", a.GetTaskLogsV1Handler)
+	})
+	r.Get("/nodes", a.ListNodesV1Handler)
+}
+
+// OpenAPIHandler handles HTTP GET requests for the OpenAPI 3 document of the API.
+func (a *Api) OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(v1.Document())
+}
+
+// CreateTaskV1Handler handles POST /v1/tasks.
+//
+// It expects a v1.CreateTaskRequest, admits the task with Manager.SubmitTask,
+// which assigns it an ID if the request has none and calls the admission
+// webhooks, and queues it to be scheduled.
+//
+// Returns:
+//   - 201 Created with the v1.Task and its URL in the Location header
+//   - 400 Bad Request listing the invalid fields of the request
+//   - 403 Forbidden if an admission webhook rejects the task
+//   - 503 Service Unavailable if an admission webhook that must admit the task
+//     cannot be called
+func (a *Api) CreateTaskV1Handler(w http.ResponseWriter, r *http.Request) {
+	var req v1.CreateTaskRequest
+	if err := v1.Validate(r, "createTask", &req); err != nil {
+		sendInvalid(w, err)
+		return
+	}
+	t, err := req.Task()
+	if err != nil {
+		sendInvalid(w, err)
+		return
+	}
+
+	te, err := a.queueTaskEvent(r, "CreateTaskV1Handler", task.Event{State: task.Scheduled, Task: t})
+	if err != nil {
+		handler.SendErr(w, submitErr(err, func(fields []handler.FieldError) []handler.FieldError {
+			return v1.TaskFieldErrors(trimTaskPrefix(fields))
+		}))
+		return
+	}
+	t = te.Task
+	w.Header().Set("Location", "/v1/tasks/"+t.ID.String())
+	sendJSON(w, http.StatusCreated, v1.NewTask(&t, ""))
+}
+
+// ListTasksV1Handler handles GET /v1/tasks.
+//
+// The tasks are filtered, sorted and paged by the query parameters read by
+// task.ParseQuery, and restricted to one worker by the worker parameter.
+//
+// Returns:
+//   - 200 OK with a v1.TaskList
+//   - 400 Bad Request if a query parameter is invalid
+//   - 500 Internal Server Error if the task store cannot be read
+func (a *Api) ListTasksV1Handler(w http.ResponseWriter, r *http.Request) {
+	if err := v1.Validate(r, "listTasks", nil); err != nil {
+		sendInvalid(w, err)
+		return
+	}
+	q, err := task.ParseQuery(r.URL.Query())
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid task query", err))
+		return
+	}
+
+	tasks, next, total, err := a.Manager.ListTasks(q, r.URL.Query().Get("worker"))
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusInternalServerError, "Error getting tasks", err))
+		return
+	}
+
+	list := v1.TaskList{Items: make([]v1.Task, len(tasks)), NextCursor: next, Total: total}
+	for i, t := range tasks {
+		worker, _ := a.Manager.workerOf(t.ID)
+		list.Items[i] = v1.NewTask(t, worker)
+	}
+	sendJSON(w, http.StatusOK, list)
+}
+
+// GetTaskV1Handler handles GET /v1/tasks/{id}.
+//
+// Returns:
+//   - 200 OK with the v1.Task
+//   - 400 Bad Request if the task ID is invalid
+//   - 404 Not Found if the task is neither scheduled on a worker nor waiting for one
+func (a *Api) GetTaskV1Handler(w http.ResponseWriter, r *http.Request) {
+	if err := v1.Validate(r, "getTask", nil); err != nil {
+		sendInvalid(w, err)
+		return
+	}
+	tID, _ := uuid.Parse(chi.URLParam(r, "id"))
+
+	t, err := a.Manager.GetTask(tID)
+	if errors.Is(err, store.ErrNotFound) {
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "Task not found", err))
+		return
+	}
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusInternalServerError, "Error getting task", err))
+		return
+	}
+	sendJSON(w, http.StatusOK, v1.NewTask(&t.Task, t.Worker))
+}
+
+// StopTaskV1Handler handles DELETE /v1/tasks/{id} by queueing a request to
+// stop the container of the task.
+//
+// Returns:
+//   - 204 No Content once the stop is queued
+//   - 400 Bad Request if the task ID is invalid
+//   - 404 Not Found if the task is neither scheduled on a worker nor waiting for one
+func (a *Api) StopTaskV1Handler(w http.ResponseWriter, r *http.Request) {
+	if err := v1.Validate(r, "stopTask", nil); err != nil {
+		sendInvalid(w, err)
+		return
+	}
+	tID, _ := uuid.Parse(chi.URLParam(r, "id"))
+
+	t, err := a.Manager.GetTask(tID)
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "Task not found", err))
+		return
+	}
+
+	// A task still waiting for a worker is cancelled when its start is dequeued.
+	te := a.Manager.StopTask(&t.Task)
+	slog.Info("Task stop requested", logging.TaskID, te.Task.ID, logging.EventID, te.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListTaskEventsV1Handler handles GET /v1/tasks/{id}/events.
+//
+// Returns:
+//   - 200 OK with a v1.TaskEventList of the dispatched events, oldest first
+//   - 400 Bad Request if the task ID is invalid
+//   - 404 Not Found if the manager knows neither the task nor any of its events
+//   - 500 Internal Server Error if the event store cannot be read
+func (a *Api) ListTaskEventsV1Handler(w http.ResponseWriter, r *http.Request) {
+	if err := v1.Validate(r, "listTaskEvents", nil); err != nil {
+		sendInvalid(w, err)
+		return
+	}
+	tID, _ := uuid.Parse(chi.URLParam(r, "id"))
+
+	events, err := a.Manager.TaskEvents(tID)
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusInternalServerError, "Error getting task events", err))
+		return
+	}
+	if len(events) == 0 {
+		if _, err := a.Manager.GetTask(tID); errors.Is(err, store.ErrNotFound) {
+			handler.SendErr(w, handler.Err(http.StatusNotFound, "Task not found", err))
+			return
+		}
+	}
+
+	list := v1.TaskEventList{Items: make([]v1.TaskEvent, len(events))}
+	for i, e := range events {
+		list.Items[i] = v1.NewTaskEvent(e)
+	}
+	sendJSON(w, http.StatusOK, list)
+}
+
+// GetTaskLogsV1Handler handles GET /v1/tasks/{id}/logs, streaming the output
+// of the task's container from its worker as plain text.
+//
+// Returns:
+//   - 200 OK with the log stream
+//   - 400 Bad Request if the task ID or a query parameter is invalid
+//   - 404 Not Found if the task is not running on a worker or has no container
+//   - 502 Bad Gateway if the worker running the task cannot be reached
+func (a *Api) GetTaskLogsV1Handler(w http.ResponseWriter, r *http.Request) {
+	if err := v1.Validate(r, "getTaskLogs", nil); err != nil {
+		sendInvalid(w, err)
+		return
+	}
+	tID, _ := uuid.Parse(chi.URLParam(r, "id"))
+
+	opts := task.LogOptions{Tail: r.URL.Query().Get("tail")}
+	opts.Follow, _ = strconv.ParseBool(r.URL.Query().Get("follow"))
+	a.sendTaskLogs(w, r, tID, opts)
+}
+
+// WatchTasksV1Handler handles GET /v1/tasks/watch, streaming v1.WatchEvent
+// messages as described for WatchTasksHandler.
+func (a *Api) WatchTasksV1Handler(w http.ResponseWriter, r *http.Request) {
+	if err := v1.Validate(r, "watchTasks", nil); err != nil {
+		sendInvalid(w, err)
+		return
+	}
+	a.watchTasks(w, r, func(e WatchEvent) any {
+		out := v1.WatchEvent{Revision: e.Revision, Type: string(e.Type)}
+		switch {
+		case e.Type == WatchTaskDeleted:
+			out.Task = &v1.Task{ID: e.Task.ID.String()}
+		case e.Task != nil:
+			worker, _ := a.Manager.workerOf(e.Task.ID)
+			t := v1.NewTask(e.Task, worker)
+			out.Task = &t
+		}
+		if e.Event != nil {
+			ev := v1.NewTaskEvent(e.Event)
+			out.Event = &ev
+		}
+		return out
+	})
+}
+
+// ListNodesV1Handler handles GET /v1/nodes.
+//
+// Returns:
+//   - 200 OK with a v1.NodeList
+func (a *Api) ListNodesV1Handler(w http.ResponseWriter, r *http.Request) {
+	nodes := a.Manager.Nodes()
+	list := v1.NodeList{Items: make([]v1.Node, len(nodes))}
+	for i, n := range nodes {
+		list.Items[i] = v1.Node{
+			Name:     n.Name,
+			Api:      n.Api,
+			Role:     n.Role,
+			Lost:     n.Lost,
+			LastSeen: n.LastSeen,
+			Tasks:    n.Tasks,
+		}
+	}
+	sendJSON(w, http.StatusOK, list)
+}
+
+// sendJSON writes v as a JSON response with the given status.
+func sendJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// sendInvalid rejects a request that failed validation, listing its invalid fields.
+func sendInvalid(w http.ResponseWriter, err error) {
+	var invalid *v1.ValidationError
+	if errors.As(err, &invalid) {
+		handler.SendErr(w, handler.ValidationErr("Invalid request", invalid.Fields))
+		return
+	}
+	handler.SendErr(w, handler.Err(http.StatusInternalServerError, "Error validating request", err))
+}
+
+// trimTaskPrefix names the field errors of a rejected task event after the
+// fields of its task, e.g. "Task.ID" as "ID".
+func trimTaskPrefix(fields []handler.FieldError) []handler.FieldError {
+	trimmed := make([]handler.FieldError, len(fields))
+	for i, f := range fields {
+		trimmed[i] = handler.FieldError{Field: strings.TrimPrefix(f.Field, "Task."), Message: f.Message}
+	}
+	return trimmed
+}
@@ -0,0 +1,174 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// WatchHistory is the number of most recent watch events kept so that watchers
+// can resume from a revision after reconnecting.
+const WatchHistory = 1000
+
+// watchBuffer is the number of watch events a watcher may fall behind before
+// it is dropped.
+const watchBuffer = 256
+
+// ErrRevisionGone is returned by Watch when the events after the requested
+// revision are no longer kept, or the revision was never reached, e.g. because
+// the manager restarted. The watcher should list the tasks again and watch from now.
+var ErrRevisionGone = errors.New("revision is no longer available")
+
+// WatchEventType is the kind of change reported by a watch event.
+type WatchEventType string
+
+const (
+	// WatchTaskChanged reports a task that was scheduled or changed state.
+	WatchTaskChanged WatchEventType = "TaskChanged"
+	// WatchTaskDeleted reports a task that was removed from the task store.
+	WatchTaskDeleted WatchEventType = "TaskDeleted"
+	// WatchTaskEvent reports a task event that was dispatched.
+	WatchTaskEvent WatchEventType = "TaskEvent"
+)
+
+// WatchEvent is a change observed by the manager.
+type WatchEvent struct {
+	// Revision increases by one with every watch event published by the manager.
+	Revision uint64
+	Type     WatchEventType
+	// Task is the task after the change; for WatchTaskEvent, the task of the event.
+	Task *task.Task `json:",omitempty"`
+	// Event is the dispatched event of a WatchTaskEvent.
+	Event *task.Event `json:",omitempty"`
+}
+
+// taskID returns the ID of the task the watch event is about.
+func (e WatchEvent) taskID() uuid.UUID {
+	if e.Task != nil {
+		return e.Task.ID
+	}
+	if e.Event != nil {
+		return e.Event.Task.ID
+	}
+	return uuid.Nil
+}
+
+// Watcher receives the watch events published after it was created.
+type Watcher struct {
+	// C delivers the watch events in revision order. It is closed when the
+	// watcher is stopped or falls too far behind; in the latter case the
+	// watcher can resume from the revision of the last event it received.
+	C <-chan WatchEvent
+
+	ch   chan WatchEvent
+	hub  *watchHub
+	once sync.Once
+}
+
+// Stop unsubscribes the watcher and closes C.
+func (w *Watcher) Stop() {
+	w.hub.mu.Lock()
+	defer w.hub.mu.Unlock()
+	w.close()
+}
+
+// close closes C once; the hub's lock must be held.
+func (w *Watcher) close() {
+	w.once.Do(func() {
+		delete(w.hub.watchers, w)
+		close(w.ch)
+	})
+}
+
+// watchHub assigns revisions to watch events, keeps the most recent ones and
+// fans them out to the watchers.
+type watchHub struct {
+	mu       sync.Mutex
+	revision uint64
+	history  []WatchEvent
+	watchers map[*Watcher]struct{}
+}
+
+// publish assigns the next revision to e and delivers it to every watcher.
+// Watchers whose buffer is full are dropped rather than blocking the caller.
+func (h *watchHub) publish(e WatchEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.revision++
+	e.Revision = h.revision
+	if len(h.history) == WatchHistory {
+		h.history = h.history[1:]
+	}
+	h.history = append(h.history, e)
+
+	for w := range h.watchers {
+		select {
+		case w.ch <- e:
+		default:
+			w.close()
+		}
+	}
+}
+
+// subscribe creates a watcher that first receives the kept events after
+// revision from, when resume is set, then every event published afterwards.
+func (h *watchHub) subscribe(from uint64, resume bool) (*Watcher, uint64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var backlog []WatchEvent
+	if resume {
+		oldest := h.revision - uint64(len(h.history))
+		if from < oldest || from > h.revision {
+			return nil, h.revision, fmt.Errorf("%w: revision %d, kept revisions %d to %d", ErrRevisionGone, from, oldest, h.revision)
+		}
+		backlog = h.history[len(h.history)-int(h.revision-from):]
+	}
+
+	ch := make(chan WatchEvent, max(watchBuffer, len(backlog)))
+	for _, e := range backlog {
+		ch <- e
+	}
+	w := &Watcher{C: ch, ch: ch, hub: h}
+	if h.watchers == nil {
+		h.watchers = make(map[*Watcher]struct{})
+	}
+	h.watchers[w] = struct{}{}
+	return w, h.revision, nil
+}
+
+// Watch subscribes to the task changes and dispatched task events of the manager.
+//
+// Parameters:
+//   - from: The revision of the last event seen by the caller; only used if resume is set
+//   - resume: Whether to replay the kept events after revision from before the new ones
+//
+// Returns:
+//   - *Watcher: The subscription; the caller must Stop it
+//   - uint64: The revision of the last event published before the subscription
+//   - error: Wrapping ErrRevisionGone if the events after from cannot be replayed
+func (m *Manager) Watch(from uint64, resume bool) (*Watcher, uint64, error) {
+	return m.watches.subscribe(from, resume)
+}
+
+// publishTask notifies watchers that a task was scheduled or changed state.
+func (m *Manager) publishTask(t *task.Task) {
+	tc := *t
+	m.watches.publish(WatchEvent{Type: WatchTaskChanged, Task: &tc})
+}
+
+// publishTaskDeleted notifies watchers that a task was removed.
+func (m *Manager) publishTaskDeleted(id uuid.UUID) {
+	m.watches.publish(WatchEvent{Type: WatchTaskDeleted, Task: &task.Task{ID: id}})
+}
+
+// publishEvent notifies watchers that a task event was dispatched.
+func (m *Manager) publishEvent(e *task.Event) {
+	ec := *e
+	ec.RegistryAuth = nil
+	m.watches.publish(WatchEvent{Type: WatchTaskEvent, Event: &ec})
+}
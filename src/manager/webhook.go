@@ -0,0 +1,321 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/utkarsh5026/Orchestra/api/v1"
+	"github.com/utkarsh5026/Orchestra/handler"
+	"github.com/utkarsh5026/Orchestra/logging"
+	"github.com/utkarsh5026/Orchestra/metrics"
+	"github.com/utkarsh5026/Orchestra/task"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"gopkg.in/yaml.v3"
+)
+
+// WebhookType is whether an admission webhook may change the tasks it admits.
+type WebhookType string
+
+const (
+	// MutatingWebhook may replace the spec of a task. Mutating webhooks are
+	// called in order, each with the spec returned by the previous one, before
+	// the task is validated.
+	MutatingWebhook WebhookType = "Mutating"
+	// ValidatingWebhook may only admit or reject a task. Validating webhooks are
+	// called after the task passes task.Task.Validate.
+	ValidatingWebhook WebhookType = "Validating"
+)
+
+// FailurePolicy is what happens to a task when its admission webhook cannot be
+// called or answers with an invalid response.
+type FailurePolicy string
+
+const (
+	// FailClosed rejects the task. This is the default.
+	FailClosed FailurePolicy = "Fail"
+	// FailOpen ignores the webhook and goes on admitting the task.
+	FailOpen FailurePolicy = "Ignore"
+)
+
+const (
+	// DefaultWebhookTimeout is the Timeout of a webhook that does not declare one.
+	DefaultWebhookTimeout = 10 * time.Second
+	// MaxWebhookTimeout bounds the Timeout of a webhook, since submissions wait
+	// for every webhook in turn.
+	MaxWebhookTimeout = 30 * time.Second
+	// maxWebhookResponse bounds the size of the response of a webhook.
+	maxWebhookResponse = 1 << 20
+)
+
+// Webhook is an admission webhook the manager calls with every task submitted
+// through the API before queuing it, and with the task templates of services,
+// cron jobs and workflows when they are created or updated. The webhook is sent a v1.AdmissionRequest
+// in a POST request and must answer 200 OK with a v1.AdmissionResponse.
+type Webhook struct {
+	Name string      `yaml:"name"`
+	Type WebhookType `yaml:"type"`
+	// URL is the http or https URL the requests are sent to.
+	URL string `yaml:"url"`
+	// Timeout bounds each call; zero means DefaultWebhookTimeout.
+	Timeout time.Duration `yaml:"timeout"`
+	// FailurePolicy is FailClosed if empty.
+	FailurePolicy FailurePolicy `yaml:"failurePolicy"`
+}
+
+// webhookConfig is the file read by LoadWebhooks.
+type webhookConfig struct {
+	Webhooks []Webhook `yaml:"webhooks"`
+}
+
+// webhookClient calls admission webhooks, propagating the trace of the submission.
+var webhookClient = &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+
+// LoadWebhooks reads the admission webhooks declared in a YAML file such as:
+//
+//	webhooks:
+//	  - name: registry-policy
+//	    type: Validating
+//	    url: https://policy.example.com/admit
+//	    timeout: 5s
+//	    failurePolicy: Fail
+//
+// Parameters:
+//   - path: The path of the file
+//
+// Returns:
+//   - []Webhook: The webhooks in the order they are declared, with defaults applied
+//   - error: If the file cannot be read or a webhook is invalid
+func LoadWebhooks(path string) ([]Webhook, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read admission config: %w", err)
+	}
+
+	var cfg webhookConfig
+	d := yaml.NewDecoder(bytes.NewReader(b))
+	d.KnownFields(true)
+	if err := d.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse admission config %s: %w", path, err)
+	}
+
+	names := make(map[string]bool, len(cfg.Webhooks))
+	for i := range cfg.Webhooks {
+		wh := &cfg.Webhooks[i]
+		if err := wh.validate(); err != nil {
+			return nil, fmt.Errorf("invalid admission webhook %d of %s: %w", i+1, path, err)
+		}
+		if names[wh.Name] {
+			return nil, fmt.Errorf("duplicate admission webhook %q in %s", wh.Name, path)
+		}
+		names[wh.Name] = true
+	}
+	return cfg.Webhooks, nil
+}
+
+// validate checks the webhook and applies the defaults of its optional fields.
+func (wh *Webhook) validate() error {
+	if wh.Name == "" {
+		return errors.New("name is required")
+	}
+	switch wh.Type {
+	case MutatingWebhook, ValidatingWebhook:
+	default:
+		return fmt.Errorf("webhook %q: type must be %s or %s", wh.Name, MutatingWebhook, ValidatingWebhook)
+	}
+	u, err := url.Parse(wh.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook %q: url must be an http or https URL", wh.Name)
+	}
+	switch {
+	case wh.Timeout < 0 || wh.Timeout > MaxWebhookTimeout:
+		return fmt.Errorf("webhook %q: timeout must be between 0 and %s", wh.Name, MaxWebhookTimeout)
+	case wh.Timeout == 0:
+		wh.Timeout = DefaultWebhookTimeout
+	}
+	switch wh.FailurePolicy {
+	case "":
+		wh.FailurePolicy = FailClosed
+	case FailClosed, FailOpen:
+	default:
+		return fmt.Errorf("webhook %q: failurePolicy must be %s or %s", wh.Name, FailClosed, FailOpen)
+	}
+	return nil
+}
+
+// AdmissionError is returned by SubmitTask when an admission webhook rejects a
+// task, or cannot be called and its FailurePolicy is FailClosed.
+type AdmissionError struct {
+	Webhook string
+	// Message and Fields are the reasons given by the webhook for rejecting the task.
+	Message string
+	Fields  []handler.FieldError
+	// Err is set if the webhook could not be called.
+	Err error
+}
+
+func (e *AdmissionError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("admission webhook %q failed: %v", e.Webhook, e.Err)
+	}
+	if e.Message == "" {
+		return fmt.Sprintf("admission webhook %q denied the task", e.Webhook)
+	}
+	return fmt.Sprintf("admission webhook %q denied the task: %s", e.Webhook, e.Message)
+}
+
+func (e *AdmissionError) Unwrap() error {
+	return e.Err
+}
+
+// callMutatingWebhooks calls the mutating webhooks with the task of an event,
+// replacing its spec with the spec each of them returns. object is the object
+// whose template the task is, or empty for a task.
+func (m *Manager) callMutatingWebhooks(ctx context.Context, te *task.Event, object string) error {
+	for _, wh := range m.Webhooks {
+		if wh.Type != MutatingWebhook {
+			continue
+		}
+		resp, err := wh.admit(ctx, te, object)
+		if err != nil {
+			if err = wh.failed(te, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if !resp.Allowed {
+			return wh.denied(te, resp)
+		}
+		if resp.Spec == nil {
+			wh.allowed()
+			continue
+		}
+		if err := applySpec(&te.Task, *resp.Spec); err != nil {
+			if err = wh.failed(te, err); err != nil {
+				return err
+			}
+			continue
+		}
+		wh.allowed()
+		slog.Info("Task mutated by admission webhook", logging.Webhook, wh.Name, logging.TaskID, te.Task.ID)
+	}
+	return nil
+}
+
+// callValidatingWebhooks calls the validating webhooks with the task of an
+// event. object is the object whose template the task is, or empty for a task.
+func (m *Manager) callValidatingWebhooks(ctx context.Context, te *task.Event, object string) error {
+	for _, wh := range m.Webhooks {
+		if wh.Type != ValidatingWebhook {
+			continue
+		}
+		resp, err := wh.admit(ctx, te, object)
+		if err != nil {
+			if err = wh.failed(te, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if !resp.Allowed {
+			return wh.denied(te, resp)
+		}
+		wh.allowed()
+	}
+	return nil
+}
+
+// applySpec replaces the spec of a task with a spec returned by a mutating
+// webhook, keeping its ID, state and inputs.
+func applySpec(t *task.Task, spec v1.TaskSpec) error {
+	mutated, err := v1.CreateTaskRequest{ID: t.ID.String(), Spec: spec}.Convert()
+	if err != nil {
+		return fmt.Errorf("returned an invalid spec: %w", err)
+	}
+	mutated.State = t.State
+	mutated.Inputs = t.Inputs
+	*t = mutated
+	return nil
+}
+
+// allowed records that the webhook admitted a task.
+func (wh Webhook) allowed() {
+	metrics.AdmissionWebhookCalls.WithLabelValues(wh.Name, "allowed").Inc()
+}
+
+// failed applies the failure policy of the webhook to an error calling it,
+// returning the error that rejects the task, or nil if it is ignored.
+func (wh Webhook) failed(te *task.Event, err error) error {
+	metrics.AdmissionWebhookCalls.WithLabelValues(wh.Name, "error").Inc()
+	if wh.FailurePolicy == FailOpen {
+		slog.Warn("Admission webhook failed, ignoring it", logging.Webhook, wh.Name, logging.TaskID, te.Task.ID, "error", err)
+		return nil
+	}
+	slog.Error("Admission webhook failed, rejecting task", logging.Webhook, wh.Name, logging.TaskID, te.Task.ID, "error", err)
+	return &AdmissionError{Webhook: wh.Name, Err: err}
+}
+
+// denied returns the error of a webhook rejecting the task.
+func (wh Webhook) denied(te *task.Event, resp *v1.AdmissionResponse) error {
+	metrics.AdmissionWebhookCalls.WithLabelValues(wh.Name, "denied").Inc()
+	slog.Info("Task denied by admission webhook", logging.Webhook, wh.Name, logging.TaskID, te.Task.ID, "message", resp.Message)
+	return &AdmissionError{Webhook: wh.Name, Message: resp.Message, Fields: resp.Fields}
+}
+
+// admit sends the task of an event to the webhook and returns its response.
+func (wh Webhook) admit(ctx context.Context, te *task.Event, object string) (*v1.AdmissionResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, wh.Timeout)
+	defer cancel()
+
+	req := v1.AdmissionRequest{
+		UID:      te.ID.String(),
+		Template: object,
+		Spec:     v1.NewTaskSpec(&te.Task),
+	}
+	if te.Task.ID != uuid.Nil {
+		req.TaskID = te.Task.ID.String()
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode admission request: %w", err)
+	}
+	hr, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	hr.Header.Set("Content-Type", "application/json")
+
+	resp, err := webhookClient.Do(hr)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponse+1))
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	case len(b) > maxWebhookResponse:
+		return nil, fmt.Errorf("response is larger than %d bytes", maxWebhookResponse)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(b)))
+	}
+
+	var ar v1.AdmissionResponse
+	if err := json.Unmarshal(b, &ar); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if ar.Spec != nil && wh.Type != MutatingWebhook {
+		return nil, errors.New("validating webhook returned a spec")
+	}
+	return &ar, nil
+}
@@ -0,0 +1,233 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/utkarsh5026/Orchestra/api/v1"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+func TestWebhookValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		wh      Webhook
+		wantErr string
+		want    Webhook
+	}{
+		{
+			name: "defaults",
+			wh:   Webhook{Name: "policy", Type: ValidatingWebhook, URL: "https://policy.example.com/admit"},
+			want: Webhook{Name: "policy", Type: ValidatingWebhook, URL: "https://policy.example.com/admit", Timeout: DefaultWebhookTimeout, FailurePolicy: FailClosed},
+		},
+		{
+			name: "explicit settings",
+			wh:   Webhook{Name: "labels", Type: MutatingWebhook, URL: "http://10.0.0.1:8080/", Timeout: 5 * time.Second, FailurePolicy: FailOpen},
+			want: Webhook{Name: "labels", Type: MutatingWebhook, URL: "http://10.0.0.1:8080/", Timeout: 5 * time.Second, FailurePolicy: FailOpen},
+		},
+		{name: "missing name", wh: Webhook{Type: ValidatingWebhook, URL: "https://a"}, wantErr: "name is required"},
+		{name: "unknown type", wh: Webhook{Name: "a", Type: "Auditing", URL: "https://a"}, wantErr: "type must be"},
+		{name: "relative url", wh: Webhook{Name: "a", Type: ValidatingWebhook, URL: "/admit"}, wantErr: "url must be"},
+		{name: "unsupported scheme", wh: Webhook{Name: "a", Type: ValidatingWebhook, URL: "ftp://a/admit"}, wantErr: "url must be"},
+		{name: "negative timeout", wh: Webhook{Name: "a", Type: ValidatingWebhook, URL: "https://a", Timeout: -time.Second}, wantErr: "timeout must be"},
+		{name: "timeout too long", wh: Webhook{Name: "a", Type: ValidatingWebhook, URL: "https://a", Timeout: MaxWebhookTimeout + time.Second}, wantErr: "timeout must be"},
+		{name: "unknown failure policy", wh: Webhook{Name: "a", Type: ValidatingWebhook, URL: "https://a", FailurePolicy: "Retry"}, wantErr: "failurePolicy must be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wh := tt.wh
+			err := wh.validate()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("validate() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validate() error = %v", err)
+			}
+			if wh != tt.want {
+				t.Errorf("validate() set %+v, want %+v", wh, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplySpec(t *testing.T) {
+	id := uuid.New()
+	inputs := []task.ArtifactInput{{TaskID: uuid.New(), Name: "data", MountPath: task.InputsDir + "/data"}}
+
+	tests := []struct {
+		name    string
+		spec    v1.TaskSpec
+		wantErr bool
+		check   func(t *testing.T, got task.Task)
+	}{
+		{
+			name: "replaces the spec",
+			spec: v1.TaskSpec{
+				Name:           "web",
+				Image:          "registry.example.com/nginx:1.27",
+				Ports:          []string{"80/tcp"},
+				Labels:         map[string]string{"team": "infra"},
+				ActiveDeadline: "1m",
+			},
+			check: func(t *testing.T, got task.Task) {
+				if got.Image != "registry.example.com/nginx:1.27" || got.Labels["team"] != "infra" || got.ActiveDeadline != time.Minute {
+					t.Errorf("task = %+v, want the spec of the webhook", got)
+				}
+				if _, ok := got.ExposedPorts["80/tcp"]; !ok {
+					t.Errorf("ExposedPorts = %v, want 80/tcp", got.ExposedPorts)
+				}
+				if got.Cpu != 0 {
+					t.Errorf("Cpu = %v, want the field dropped from the spec to be cleared", got.Cpu)
+				}
+			},
+		},
+		{
+			name: "keeps the ID, state and inputs",
+			spec: v1.TaskSpec{Image: "nginx:1.27"},
+			check: func(t *testing.T, got task.Task) {
+				if got.ID != id || got.State != task.Pending || !slices.Equal(got.Inputs, inputs) {
+					t.Errorf("task has ID %v, state %v and inputs %v, want %v, Pending and %v", got.ID, got.State, got.Inputs, id, inputs)
+				}
+			},
+		},
+		{name: "invalid duration", spec: v1.TaskSpec{Image: "nginx:1.27", StopGracePeriod: "soon"}, wantErr: true},
+		{name: "invalid port", spec: v1.TaskSpec{Image: "nginx:1.27", Ports: []string{"http"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := task.Task{ID: id, Name: "app", Image: "nginx:1.26", Cpu: 1, State: task.Pending, Inputs: inputs}
+			got := orig
+			err := applySpec(&got, tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("applySpec() error = nil, want an error")
+				}
+				if got.Image != orig.Image || got.Cpu != orig.Cpu {
+					t.Errorf("task = %+v after a failed applySpec, want it unchanged", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applySpec() error = %v", err)
+			}
+			tt.check(t, got)
+		})
+	}
+}
+
+// webhookServer returns a webhook of the given type answering every request
+// with resp.
+func webhookServer(t *testing.T, name string, typ WebhookType, resp func(v1.AdmissionRequest) v1.AdmissionResponse) Webhook {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req v1.AdmissionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(resp(req))
+	}))
+	t.Cleanup(srv.Close)
+	wh := Webhook{Name: name, Type: typ, URL: srv.URL}
+	if err := wh.validate(); err != nil {
+		t.Fatalf("validate() error = %v", err)
+	}
+	return wh
+}
+
+func TestSubmitTaskCallsWebhooks(t *testing.T) {
+	addLabel := func(req v1.AdmissionRequest) v1.AdmissionResponse {
+		spec := req.Spec
+		spec.Labels = map[string]string{"admitted": "true"}
+		return v1.AdmissionResponse{Allowed: true, Spec: &spec}
+	}
+	requireLabel := func(req v1.AdmissionRequest) v1.AdmissionResponse {
+		if req.Spec.Labels["admitted"] != "true" {
+			return v1.AdmissionResponse{Message: "not mutated"}
+		}
+		return v1.AdmissionResponse{Allowed: true}
+	}
+	deny := func(v1.AdmissionRequest) v1.AdmissionResponse {
+		return v1.AdmissionResponse{Message: "images must come from the internal registry"}
+	}
+	unreachable := func(policy FailurePolicy) Webhook {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		srv.Close()
+		return Webhook{Name: "down", Type: ValidatingWebhook, URL: srv.URL, Timeout: time.Second, FailurePolicy: policy}
+	}
+
+	tests := []struct {
+		name     string
+		webhooks func(t *testing.T) []Webhook
+		// denied is the webhook expected to reject the task, or empty if it is admitted.
+		denied string
+		// failed reports whether the webhook is expected to have failed rather than denied the task.
+		failed bool
+	}{
+		{
+			name: "mutated then validated",
+			webhooks: func(t *testing.T) []Webhook {
+				return []Webhook{
+					webhookServer(t, "require-label", ValidatingWebhook, requireLabel),
+					webhookServer(t, "add-label", MutatingWebhook, addLabel),
+				}
+			},
+		},
+		{
+			name: "denied",
+			webhooks: func(t *testing.T) []Webhook {
+				return []Webhook{webhookServer(t, "registry-policy", ValidatingWebhook, deny)}
+			},
+			denied: "registry-policy",
+		},
+		{
+			name:     "unreachable and failing closed",
+			webhooks: func(t *testing.T) []Webhook { return []Webhook{unreachable(FailClosed)} },
+			denied:   "down",
+			failed:   true,
+		},
+		{
+			name:     "unreachable and failing open",
+			webhooks: func(t *testing.T) []Webhook { return []Webhook{unreachable(FailOpen)} },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager()
+			m.Webhooks = tt.webhooks(t)
+
+			te, err := m.SubmitTask(context.Background(), task.Event{Task: task.Task{Image: "nginx:1.27"}})
+			if tt.denied == "" {
+				if err != nil {
+					t.Fatalf("SubmitTask() error = %v", err)
+				}
+				if !m.hasTask(te.Task.ID) {
+					t.Error("admitted task was not queued")
+				}
+				return
+			}
+
+			var denied *AdmissionError
+			if !errors.As(err, &denied) || denied.Webhook != tt.denied || (denied.Err != nil) != tt.failed {
+				t.Fatalf("SubmitTask() error = %v, want an *AdmissionError of %q", err, tt.denied)
+			}
+			if m.Pending.Len() != 0 {
+				t.Error("rejected task was queued")
+			}
+		})
+	}
+}
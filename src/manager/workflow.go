@@ -0,0 +1,175 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/flow"
+	"github.com/utkarsh5026/Orchestra/logging"
+	"github.com/utkarsh5026/Orchestra/manifest"
+	"github.com/utkarsh5026/Orchestra/task"
+	"github.com/utkarsh5026/Orchestra/utils"
+)
+
+// SubmitWorkflow admits the tasks of the steps of a workflow, stores the
+// workflow and dispatches the steps that have no dependencies.
+//
+// Parameters:
+//   - ctx: Context bounding the calls of the admission webhooks
+//   - name: The name of the workflow
+//   - steps: The steps to run, as passed to flow.New
+//
+// Returns:
+//   - *flow.Workflow: The submitted workflow
+//   - error: Wrapping ErrInvalidObject if the steps are not a valid DAG, an
+//     *AdmissionError if a webhook rejected the task of a step, or the error of
+//     the workflow store update
+func (m *Manager) SubmitWorkflow(ctx context.Context, name string, steps []*flow.Step) (*flow.Workflow, error) {
+	key := manifest.Key(manifest.KindWorkflow, name)
+	for i, s := range steps {
+		if err := m.AdmitTemplate(ctx, &s.Task, key, fmt.Sprintf("Steps[%d].Task.", i)); err != nil {
+			return nil, err
+		}
+	}
+	wf, err := flow.New(name, steps)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidObject, err)
+	}
+
+	m.workflowsMu.Lock()
+	defer m.workflowsMu.Unlock()
+
+	if err := m.Workflows.Put(wf.ID.String(), wf); err != nil {
+		return nil, fmt.Errorf("failed to store workflow %s: %w", wf.ID, err)
+	}
+	m.updateWorkflow(wf)
+	return wf, nil
+}
+
+// GetWorkflows returns all workflows known to the manager.
+//
+// Returns:
+//   - []*flow.Workflow: A slice containing pointers to all workflows
+//   - error: Any error that occurred while reading the workflow store
+func (m *Manager) GetWorkflows() ([]*flow.Workflow, error) {
+	return m.Workflows.List()
+}
+
+// UpdateWorkflows periodically advances all unfinished workflows.
+//
+// Parameters:
+//   - ctx: Context whose cancellation stops the loop
+//   - d: The duration to wait between updates
+//
+// This function runs until ctx is cancelled and should be started in a separate goroutine.
+func (m *Manager) UpdateWorkflows(ctx context.Context, d time.Duration) {
+	for {
+		m.updateWorkflows()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d):
+		}
+	}
+}
+
+func (m *Manager) updateWorkflows() {
+	wfs, err := m.Workflows.List()
+	if err != nil {
+		slog.Error("Error listing workflows", "error", err)
+		return
+	}
+
+	for _, wf := range wfs {
+		m.workflowsMu.Lock()
+		// Skip the workflows deleted since they were listed.
+		if cur, err := m.Workflows.Get(wf.ID.String()); err == nil && cur == wf && !wf.IsFinished() {
+			m.updateWorkflow(wf)
+		}
+		m.workflowsMu.Unlock()
+	}
+}
+
+// updateWorkflow refreshes the state of each dispatched step from its task, skips
+// the steps downstream of failures and dispatches the steps that became ready.
+// The caller must hold workflowsMu.
+func (m *Manager) updateWorkflow(wf *flow.Workflow) {
+	for _, s := range wf.Steps {
+		if s.State != flow.Running {
+			continue
+		}
+		t, err := m.TaskStore.Get(s.TaskID.String())
+		if err != nil {
+			// The task has not been scheduled on a worker yet.
+			continue
+		}
+		s.Observe(t)
+	}
+
+	wf.Propagate()
+	for _, s := range wf.Ready() {
+		t := s.NewTask(m.upstream(wf, s))
+		s.TaskID = t.ID
+		s.State = flow.Running
+		slog.Info("Dispatching step of workflow", logging.Workflow, wf.ID, "step", s.Name, logging.TaskID, t.ID)
+		m.AddTask(task.Event{
+			ID:        uuid.New(),
+			State:     task.Scheduled,
+			Timestamp: time.Now(),
+			Task:      t,
+		})
+	}
+
+	wf.UpdateState()
+	utils.UpdateStore(m.Workflows, wf.ID.String(), wf)
+}
+
+// upstream returns the completed steps s depends on, with their tasks and the
+// workers that ran them.
+func (m *Manager) upstream(wf *flow.Workflow, s *flow.Step) []flow.Upstream {
+	var ups []flow.Upstream
+	for _, dep := range s.DependsOn {
+		id := wf.Step(dep).TaskID
+		t, err := m.TaskStore.Get(id.String())
+		if err != nil {
+			slog.Warn("Task of step not found in task store", logging.Workflow, wf.ID, "step", dep, logging.TaskID, id)
+			continue
+		}
+		worker, _ := m.workerOf(id)
+		ups = append(ups, flow.Upstream{Step: dep, Task: t, Worker: worker})
+	}
+	return ups
+}
+
+// DeleteWorkflow stops the tasks of the running steps of a workflow and removes it.
+//
+// Parameters:
+//   - id: The ID of the workflow
+//
+// Returns:
+//   - error if the workflow does not exist or cannot be deleted
+func (m *Manager) DeleteWorkflow(id uuid.UUID) error {
+	m.workflowsMu.Lock()
+	defer m.workflowsMu.Unlock()
+
+	wf, err := m.Workflows.Get(id.String())
+	if err != nil {
+		return err
+	}
+
+	for _, s := range wf.Steps {
+		if s.State != flow.Running {
+			continue
+		}
+		t, err := m.TaskStore.Get(s.TaskID.String())
+		if err != nil {
+			// The task has not been scheduled yet; stop it from its spec.
+			t = &task.Task{ID: s.TaskID}
+		}
+		m.StopTask(t)
+	}
+	return m.Workflows.Delete(id.String())
+}
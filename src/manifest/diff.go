@@ -0,0 +1,117 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Action is what applying a manifest does to an object.
+type Action string
+
+const (
+	ActionCreate    Action = "create"
+	ActionUpdate    Action = "update"
+	ActionReplace   Action = "replace"
+	ActionDelete    Action = "delete"
+	ActionUnchanged Action = "unchanged"
+)
+
+// Change is the difference between the declared and the current state of an object.
+type Change struct {
+	Kind   Kind
+	Name   string
+	Action Action
+	// Fields lists the spec fields that differ when the object is updated or replaced.
+	Fields []FieldDiff `json:",omitempty"`
+}
+
+// Key returns the identity of the changed object as "<kind>/<name>".
+func (c Change) Key() string {
+	return Key(c.Kind, c.Name)
+}
+
+// FieldDiff is a spec field whose current value differs from the declared one.
+type FieldDiff struct {
+	// Path is the dotted path of the field, e.g. "Template.Image" or "Steps[1].DependsOn".
+	Path    string
+	Current any `json:",omitempty"`
+	Desired any `json:",omitempty"`
+}
+
+// Diff compares two specs of the same type field by field, using their JSON form.
+//
+// Parameters:
+//   - current: The spec of the existing object
+//   - desired: The declared spec
+//
+// Returns:
+//   - []FieldDiff: The differing fields, in a stable order; empty if the specs are equal
+//   - error: If either spec cannot be encoded
+func Diff(current, desired any) ([]FieldDiff, error) {
+	c, err := generic(current)
+	if err != nil {
+		return nil, err
+	}
+	d, err := generic(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []FieldDiff
+	diffValues("", c, d, &diffs)
+	return diffs, nil
+}
+
+// generic returns v decoded from its JSON form into maps, slices and scalars.
+func generic(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var g any
+	if err := json.Unmarshal(b, &g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func diffValues(path string, current, desired any, diffs *[]FieldDiff) {
+	cm, cok := current.(map[string]any)
+	dm, dok := desired.(map[string]any)
+	if cok && dok {
+		keys := slices.Sorted(maps.Keys(cm))
+		for k := range dm {
+			if _, ok := cm[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			diffValues(join(path, k), cm[k], dm[k], diffs)
+		}
+		return
+	}
+
+	cs, cok := current.([]any)
+	ds, dok := desired.([]any)
+	if cok && dok && len(cs) == len(ds) {
+		for i := range cs {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), cs[i], ds[i], diffs)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(current, desired) {
+		*diffs = append(*diffs, FieldDiff{Path: path, Current: current, Desired: desired})
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
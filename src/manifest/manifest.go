@@ -0,0 +1,128 @@
+// Package manifest describes tasks, services, cron jobs and workflows
+// declaratively, so that they can be kept in files and applied to a manager.
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kind is the type of object a manifest declares.
+type Kind string
+
+const (
+	KindTask     Kind = "Task"
+	KindService  Kind = "Service"
+	KindCronJob  Kind = "CronJob"
+	KindWorkflow Kind = "Workflow"
+)
+
+// Kinds lists every kind in the order objects are applied.
+var Kinds = []Kind{KindTask, KindService, KindCronJob, KindWorkflow}
+
+// Object is a declared object. Objects are identified by their kind and name.
+type Object struct {
+	Kind Kind
+	Name string
+	// Spec is the desired state in the JSON form of the kind's spec type:
+	// task.Task, ServiceSpec, CronJobSpec or WorkflowSpec.
+	Spec json.RawMessage
+}
+
+// Key returns the identity of the object as "<kind>/<name>".
+func (o Object) Key() string {
+	return Key(o.Kind, o.Name)
+}
+
+// Key returns the identity of an object of the given kind and name.
+func Key(kind Kind, name string) string {
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+// document is a single YAML document of a manifest file.
+type document struct {
+	Kind Kind   `yaml:"kind"`
+	Name string `yaml:"name"`
+	Spec any    `yaml:"spec"`
+}
+
+// Parse reads a stream of YAML documents, each declaring one object:
+//
+//	kind: Service
+//	name: web
+//	spec:
+//	  Replicas: 3
+//	  Template:
+//	    Image: nginx:1.27
+//
+// Field names of the spec are those of the JSON API and are matched without
+// regard to case. Durations such as StopGracePeriod or ScaleDownStabilization
+// may be written as strings like "30s". Empty documents are skipped.
+//
+// Parameters:
+//   - r: The YAML stream
+//
+// Returns:
+//   - []Object: The declared objects, in the order they appear
+//   - error: If a document is malformed, has an unknown kind or no name
+func Parse(r io.Reader) ([]Object, error) {
+	dec := yaml.NewDecoder(r)
+	var objs []Object
+	for i := 1; ; i++ {
+		var doc document
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		if doc.Kind == "" && doc.Name == "" && doc.Spec == nil {
+			continue
+		}
+
+		spec, err := json.Marshal(doc.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		o := Object{Kind: doc.Kind, Name: doc.Name, Spec: spec}
+		if err := o.validate(); err != nil {
+			return nil, fmt.Errorf("document %d: %w", i, err)
+		}
+		objs = append(objs, o)
+	}
+}
+
+// Validate checks that every object has a known kind and a name, and that no
+// two objects have the same kind and name.
+func Validate(objs []Object) error {
+	seen := make(map[string]bool, len(objs))
+	for _, o := range objs {
+		if err := o.validate(); err != nil {
+			return err
+		}
+		if seen[o.Key()] {
+			return fmt.Errorf("%s is declared more than once", o.Key())
+		}
+		seen[o.Key()] = true
+	}
+	return nil
+}
+
+func (o Object) validate() error {
+	switch o.Kind {
+	case KindTask, KindService, KindCronJob, KindWorkflow:
+	case "":
+		return errors.New("kind is required")
+	default:
+		return fmt.Errorf("unknown kind %q", o.Kind)
+	}
+	if o.Name == "" {
+		return fmt.Errorf("%s: name is required", o.Kind)
+	}
+	return nil
+}
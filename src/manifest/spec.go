@@ -0,0 +1,248 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/utkarsh5026/Orchestra/cronjob"
+	"github.com/utkarsh5026/Orchestra/flow"
+	"github.com/utkarsh5026/Orchestra/service"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// ServiceSpec is the declared part of a service.
+type ServiceSpec struct {
+	Template task.Task
+	// Replicas is ignored while Autoscaling is set, except to pick the initial count.
+	Replicas             int
+	Strategy             service.Strategy
+	RevisionHistoryLimit int                  `json:",omitempty"`
+	Autoscaling          *service.Autoscaling `json:",omitempty"`
+}
+
+// CronJobSpec is the declared part of a cron job.
+type CronJobSpec struct {
+	Schedule          string
+	Template          task.Task
+	ConcurrencyPolicy cronjob.ConcurrencyPolicy `json:",omitempty"`
+	CatchUpLimit      int                       `json:",omitempty"`
+	HistoryLimit      int                       `json:",omitempty"`
+}
+
+// WorkflowSpec is the declared part of a workflow.
+type WorkflowSpec struct {
+	Steps []StepSpec
+}
+
+// StepSpec is the declared part of a workflow step.
+type StepSpec struct {
+	Name      string
+	Task      task.Task
+	DependsOn []string `json:",omitempty"`
+}
+
+// TaskSpec returns t without the fields that record its progress, such as its
+// ID, state, container and exit code, and without the inputs the manager sets.
+func TaskSpec(t task.Task) task.Task {
+	return task.Task{
+		Name:             t.Name,
+		Image:            t.Image,
+		Memory:           t.Memory,
+		Disk:             t.Disk,
+		Cpu:              t.Cpu,
+		ExposedPorts:     t.ExposedPorts,
+		RestartPolicy:    t.RestartPolicy,
+		PortBindings:     t.PortBindings,
+		PullPolicy:       t.PullPolicy,
+		ImagePullSecret:  t.ImagePullSecret,
+		StopSignal:       t.StopSignal,
+		StopGracePeriod:  t.StopGracePeriod,
+		PreStop:          t.PreStop,
+		Kind:             t.Kind,
+		ActiveDeadline:   t.ActiveDeadline,
+		BackoffLimit:     t.BackoffLimit,
+		TTLAfterFinished: t.TTLAfterFinished,
+		Env:              t.Env,
+		Artifacts:        t.Artifacts,
+		OutputsFile:      t.OutputsFile,
+		HealthCheck:      t.HealthCheck,
+		Labels:           t.Labels,
+	}
+}
+
+// ServiceSpecOf returns the declared part of svc.
+func ServiceSpecOf(svc *service.Service) ServiceSpec {
+	spec := ServiceSpec{
+		Template:             TaskSpec(svc.Template),
+		Replicas:             svc.Replicas,
+		Strategy:             svc.Strategy,
+		RevisionHistoryLimit: svc.RevisionHistoryLimit,
+	}
+	if svc.Autoscaling != nil {
+		as := *svc.Autoscaling
+		as.Recommendations = nil
+		as.LastScaleTime = time.Time{}
+		spec.Autoscaling = &as
+	}
+	return spec
+}
+
+// CronJobSpecOf returns the declared part of cj.
+func CronJobSpecOf(cj *cronjob.CronJob) CronJobSpec {
+	return CronJobSpec{
+		Schedule:          cj.Schedule,
+		Template:          TaskSpec(cj.Template),
+		ConcurrencyPolicy: cj.ConcurrencyPolicy,
+		CatchUpLimit:      cj.CatchUpLimit,
+		HistoryLimit:      cj.HistoryLimit,
+	}
+}
+
+// WorkflowSpecOf returns the declared part of wf.
+func WorkflowSpecOf(wf *flow.Workflow) WorkflowSpec {
+	spec := WorkflowSpec{Steps: make([]StepSpec, 0, len(wf.Steps))}
+	for _, s := range wf.Steps {
+		spec.Steps = append(spec.Steps, StepSpec{Name: s.Name, Task: TaskSpec(s.Task), DependsOn: s.DependsOn})
+	}
+	return spec
+}
+
+// Task decodes the spec of a Task object. The name of the object is the name of the task.
+func (o Object) Task() (task.Task, error) {
+	var t task.Task
+	if err := o.decode(&t); err != nil {
+		return task.Task{}, err
+	}
+	t = TaskSpec(t)
+	t.Name = o.Name
+	if fields := t.ValidateTemplate(""); len(fields) > 0 {
+		return task.Task{}, fmt.Errorf("%s: %w", o.Key(), &task.ValidationError{Fields: fields})
+	}
+	return t, nil
+}
+
+// Service decodes the spec of a Service object into a new, validated service.
+func (o Object) Service() (*service.Service, error) {
+	var spec ServiceSpec
+	if err := o.decode(&spec); err != nil {
+		return nil, err
+	}
+	svc, err := service.New(service.Service{
+		Name:                 o.Name,
+		Template:             TaskSpec(spec.Template),
+		Replicas:             spec.Replicas,
+		Strategy:             spec.Strategy,
+		RevisionHistoryLimit: spec.RevisionHistoryLimit,
+		Autoscaling:          spec.Autoscaling,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", o.Key(), err)
+	}
+	return svc, nil
+}
+
+// CronJob decodes the spec of a CronJob object into a new, validated cron job.
+func (o Object) CronJob() (*cronjob.CronJob, error) {
+	var spec CronJobSpec
+	if err := o.decode(&spec); err != nil {
+		return nil, err
+	}
+	cj, err := cronjob.New(cronjob.CronJob{
+		Name:              o.Name,
+		Schedule:          spec.Schedule,
+		Template:          TaskSpec(spec.Template),
+		ConcurrencyPolicy: spec.ConcurrencyPolicy,
+		CatchUpLimit:      spec.CatchUpLimit,
+		HistoryLimit:      spec.HistoryLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", o.Key(), err)
+	}
+	return cj, nil
+}
+
+// Workflow decodes the spec of a Workflow object into a new, validated workflow.
+func (o Object) Workflow() (*flow.Workflow, error) {
+	var spec WorkflowSpec
+	if err := o.decode(&spec); err != nil {
+		return nil, err
+	}
+	steps := make([]*flow.Step, 0, len(spec.Steps))
+	for _, s := range spec.Steps {
+		steps = append(steps, &flow.Step{Name: s.Name, Task: TaskSpec(s.Task), DependsOn: s.DependsOn})
+	}
+	wf, err := flow.New(o.Name, steps)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", o.Key(), err)
+	}
+	return wf, nil
+}
+
+// durationFields are the spec fields holding a time.Duration, in lower case.
+var durationFields = map[string]bool{
+	"stopgraceperiod":        true,
+	"activedeadline":         true,
+	"ttlafterfinished":       true,
+	"scaleupstabilization":   true,
+	"scaledownstabilization": true,
+}
+
+// decode decodes the spec into v, rejecting fields v does not have. Durations
+// may be given as strings such as "30s" or as a number of nanoseconds.
+func (o Object) decode(v any) error {
+	var raw any
+	d := json.NewDecoder(bytes.NewReader(o.Spec))
+	d.UseNumber()
+	if err := d.Decode(&raw); err != nil {
+		return fmt.Errorf("%s: invalid spec: %w", o.Key(), err)
+	}
+	if err := parseDurations(raw); err != nil {
+		return fmt.Errorf("%s: invalid spec: %w", o.Key(), err)
+	}
+	spec, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("%s: invalid spec: %w", o.Key(), err)
+	}
+
+	d = json.NewDecoder(bytes.NewReader(spec))
+	d.DisallowUnknownFields()
+	if err := d.Decode(v); err != nil {
+		return fmt.Errorf("%s: invalid spec: %w", o.Key(), err)
+	}
+	return nil
+}
+
+// parseDurations replaces the duration strings of the durationFields found in
+// the decoded JSON value v with their number of nanoseconds. Labels are left
+// alone, since their keys are chosen by users.
+func parseDurations(v any) error {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			if strings.EqualFold(k, "Labels") {
+				continue
+			}
+			if s, ok := e.(string); ok && durationFields[strings.ToLower(k)] {
+				dur, err := time.ParseDuration(s)
+				if err != nil {
+					return fmt.Errorf("%s: %w", k, err)
+				}
+				v[k] = int64(dur)
+				continue
+			}
+			if err := parseDurations(e); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, e := range v {
+			if err := parseDurations(e); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Package metrics defines the Prometheus collectors exported by the manager and
+// worker on their /metrics endpoints.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "orchestra"
+
+var (
+	// ManagerTasks is the number of tasks known to the manager by state.
+	ManagerTasks = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "manager",
+		Name:      "tasks",
+		Help:      "Number of tasks in the manager's task store by state.",
+	}, []string{"state"})
+
+	// PendingTasks is the number of task events waiting to be sent to a worker.
+	PendingTasks = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "manager",
+		Name:      "pending_tasks",
+		Help:      "Number of task events in the manager's pending queue.",
+	})
+
+	// SchedulingLatency is the time from a task event being queued to the task
+	// being accepted by a worker.
+	SchedulingLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "manager",
+		Name:      "scheduling_latency_seconds",
+		Help:      "Time from a task event being queued to it being accepted by a worker.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 14),
+	})
+
+	// SchedulingErrors counts task events that could not be sent to a worker.
+	SchedulingErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "manager",
+		Name:      "scheduling_errors_total",
+		Help:      "Number of task events that could not be sent to a worker.",
+	})
+
+	// AdmissionWebhookCalls counts the calls of each admission webhook by result.
+	AdmissionWebhookCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "manager",
+		Name:      "admission_webhook_calls_total",
+		Help:      "Number of calls of an admission webhook by result: allowed, denied or error.",
+	}, []string{"webhook", "result"})
+
+	// WorkerPollErrors counts failed polls of a worker's tasks.
+	WorkerPollErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "manager",
+		Name:      "worker_poll_errors_total",
+		Help:      "Number of failed requests for the tasks of a worker.",
+	}, []string{"worker"})
+
+	// WorkerUp reports whether a worker answered its last task poll.
+	WorkerUp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "manager",
+		Name:      "worker_up",
+		Help:      "Whether the worker answered the last poll of its tasks (1) or not (0).",
+	}, []string{"worker"})
+
+	// WorkerTasks is the number of tasks tracked by a worker by state.
+	WorkerTasks = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "worker",
+		Name:      "tasks",
+		Help:      "Number of tasks in the worker's task store by state.",
+	}, []string{"state"})
+
+	// DockerOperationDuration is the duration of Docker API operations.
+	DockerOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "docker",
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of Docker operations by operation and outcome.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 16),
+	}, []string{"operation", "outcome"})
+)
+
+// Handler returns the HTTP handler serving all registered metrics in the
+// Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveDockerOperation records the duration of a Docker operation that started
+// at start, labelled as an error if err is not nil.
+func ObserveDockerOperation(operation string, start time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	DockerOperationDuration.WithLabelValues(operation, outcome).Observe(time.Since(start).Seconds())
+}
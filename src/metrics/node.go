@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/utkarsh5026/Orchestra/node"
+)
+
+// NodeCollector exports the resource usage of the host from node.GetStats. The
+// stats are read on every scrape, which takes about a second to measure CPU usage.
+type NodeCollector struct {
+	cpuUsage    *prometheus.Desc
+	cpuCount    *prometheus.Desc
+	memoryTotal *prometheus.Desc
+	memoryUsed  *prometheus.Desc
+	memoryAvail *prometheus.Desc
+}
+
+// NewNodeCollector creates a collector labelled with the name of the node.
+func NewNodeCollector(name string) *NodeCollector {
+	labels := prometheus.Labels{"node": name}
+	desc := func(metric, help string, variable ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "node", metric), help, variable, labels)
+	}
+	return &NodeCollector{
+		cpuUsage:    desc("cpu_usage_percent", "CPU usage of the node by CPU.", "cpu"),
+		cpuCount:    desc("cpus", "Number of logical CPUs of the node."),
+		memoryTotal: desc("memory_total_bytes", "Total memory of the node."),
+		memoryUsed:  desc("memory_used_bytes", "Memory used on the node."),
+		memoryAvail: desc("memory_available_bytes", "Memory available on the node."),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *NodeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.cpuUsage
+	ch <- c.cpuCount
+	ch <- c.memoryTotal
+	ch <- c.memoryUsed
+	ch <- c.memoryAvail
+}
+
+// Collect implements prometheus.Collector.
+func (c *NodeCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := node.GetStats()
+
+	for i, usage := range stats.Cpu.Usages {
+		ch <- prometheus.MustNewConstMetric(c.cpuUsage, prometheus.GaugeValue, usage, strconv.Itoa(i))
+	}
+	ch <- prometheus.MustNewConstMetric(c.cpuCount, prometheus.GaugeValue, float64(stats.Cpu.Count))
+	ch <- prometheus.MustNewConstMetric(c.memoryTotal, prometheus.GaugeValue, float64(stats.Memory.Total))
+	ch <- prometheus.MustNewConstMetric(c.memoryUsed, prometheus.GaugeValue, float64(stats.Memory.Usage))
+	ch <- prometheus.MustNewConstMetric(c.memoryAvail, prometheus.GaugeValue, float64(stats.Memory.Available))
+}
+
+// RegisterNode registers a NodeCollector for the named node with the default
+// registry. Registering the same node twice is not an error.
+func RegisterNode(name string) error {
+	err := prometheus.Register(NewNodeCollector(name))
+	if errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+		return nil
+	}
+	return err
+}
@@ -4,7 +4,7 @@ import (
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/mem"
-	"log"
+	"log/slog"
 	"time"
 )
 
@@ -46,17 +46,17 @@ func GetStats() *Stats {
 func getCpuInfo() CpuStats {
 	percent, err := cpu.Percent(time.Second, true)
 	if err != nil {
-		log.Printf("Error getting CPU info: %v\n", err)
+		slog.Error("Error getting CPU usage", "error", err)
 	}
 
 	cpuCnt, err := cpu.Counts(true)
 	if err != nil {
-		log.Printf("Error getting CPU count: %v\n", err)
+		slog.Error("Error getting CPU count", "error", err)
 	}
 
 	info, err := cpu.Info()
 	if err != nil {
-		log.Printf("Error getting CPU info: %v\n", err)
+		slog.Error("Error getting CPU info", "error", err)
 	}
 
 	return CpuStats{
@@ -69,7 +69,7 @@ func getCpuInfo() CpuStats {
 func getDiskInfo() DiskStats {
 	partitions, err := disk.Partitions(true)
 	if err != nil {
-		log.Printf("Error getting disk info: %v\n", err)
+		slog.Error("Error getting disk info", "error", err)
 	}
 
 	return DiskStats{
@@ -80,7 +80,7 @@ func getDiskInfo() DiskStats {
 func getMemoryInfo() MemoryStats {
 	vmStat, err := mem.VirtualMemory()
 	if err != nil {
-		log.Printf("Error getting memory info: %v\n", err)
+		slog.Error("Error getting memory info", "error", err)
 	}
 
 	return MemoryStats{
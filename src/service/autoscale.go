@@ -0,0 +1,139 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// Metric is the resource whose utilization drives autoscaling.
+type Metric string
+
+const (
+	MetricCPU    Metric = "cpu"
+	MetricMemory Metric = "memory"
+)
+
+const (
+	// DefaultScaleDownStabilization is used when ScaleDownStabilization is zero.
+	DefaultScaleDownStabilization = 5 * time.Minute
+	// tolerance is the relative deviation from the target within which no scaling happens.
+	tolerance = 0.1
+)
+
+// Autoscaling adjusts the replica count of a service so that the average
+// utilization of its tasks stays close to a target.
+type Autoscaling struct {
+	MinReplicas int
+	MaxReplicas int
+	Metric      Metric
+	// TargetUtilization is the desired average utilization in percent. For CPU it
+	// is relative to the CPUs requested by the template, or to one CPU if none are.
+	// For memory it is relative to the container's memory limit.
+	TargetUtilization float64
+	// ScaleUpStabilization and ScaleDownStabilization are windows over which
+	// recommendations are considered; the most conservative one is applied.
+	ScaleUpStabilization   time.Duration `json:",omitempty"`
+	ScaleDownStabilization time.Duration `json:",omitempty"`
+	// Recommendations holds the recent replica counts computed from the metric.
+	Recommendations []Recommendation `json:",omitempty"`
+	LastScaleTime   time.Time
+}
+
+// Recommendation is a replica count computed from the metric at some point in time.
+type Recommendation struct {
+	Replicas int
+	Time     time.Time
+}
+
+// Validate checks the bounds, metric and target of the autoscaling settings.
+func (a *Autoscaling) Validate() error {
+	if a.MinReplicas < 1 || a.MaxReplicas < a.MinReplicas {
+		return fmt.Errorf("invalid replica bounds %d..%d", a.MinReplicas, a.MaxReplicas)
+	}
+	if a.Metric != MetricCPU && a.Metric != MetricMemory {
+		return fmt.Errorf("invalid metric %q", a.Metric)
+	}
+	if a.TargetUtilization <= 0 {
+		return errors.New("target utilization must be positive")
+	}
+	if a.ScaleUpStabilization < 0 || a.ScaleDownStabilization < 0 {
+		return errors.New("stabilization windows must not be negative")
+	}
+	return nil
+}
+
+// Utilization returns the utilization of a task for the metric, in percent.
+//
+// Returns:
+//   - float64: The utilization
+//   - bool: false if the task has no usage sample yet
+func (a *Autoscaling) Utilization(t *task.Task) (float64, bool) {
+	if t.Usage == nil {
+		return 0, false
+	}
+	if a.Metric == MetricMemory {
+		return t.Usage.MemoryPercent, true
+	}
+	if t.Cpu > 0 {
+		return t.Usage.CPUPercent / t.Cpu, true
+	}
+	return t.Usage.CPUPercent, true
+}
+
+// Recommend records the replica count that brings the average utilization to the
+// target and returns the count to scale to after applying the stabilization windows.
+//
+// Parameters:
+//   - current: The current replica count
+//   - average: The average utilization of the ready tasks, in percent
+//   - now: The current time
+//
+// Returns:
+//   - int: The stabilized replica count, between MinReplicas and MaxReplicas
+func (a *Autoscaling) Recommend(current int, average float64, now time.Time) int {
+	desired := current
+	ratio := average / a.TargetUtilization
+	if math.Abs(ratio-1) > tolerance {
+		desired = int(math.Ceil(float64(current) * ratio))
+	}
+	desired = min(max(desired, a.MinReplicas), a.MaxReplicas)
+
+	a.Recommendations = append(a.Recommendations, Recommendation{Replicas: desired, Time: now})
+	window := max(a.ScaleUpStabilization, a.scaleDownWindow())
+	a.Recommendations = slices.DeleteFunc(a.Recommendations, func(r Recommendation) bool {
+		return now.Sub(r.Time) > window
+	})
+
+	// Scale up only as far as every recommendation in the scale-up window allows,
+	// and down only as far as every recommendation in the scale-down window allows.
+	upTo, downTo := desired, desired
+	for _, r := range a.Recommendations {
+		age := now.Sub(r.Time)
+		if age <= a.ScaleUpStabilization {
+			upTo = min(upTo, r.Replicas)
+		}
+		if age <= a.scaleDownWindow() {
+			downTo = max(downTo, r.Replicas)
+		}
+	}
+
+	switch {
+	case desired > current:
+		return max(upTo, current)
+	case desired < current:
+		return min(downTo, current)
+	}
+	return current
+}
+
+func (a *Autoscaling) scaleDownWindow() time.Duration {
+	if a.ScaleDownStabilization == 0 {
+		return DefaultScaleDownStabilization
+	}
+	return a.ScaleDownStabilization
+}
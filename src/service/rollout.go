@@ -0,0 +1,135 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// DefaultRevisionHistoryLimit is the number of revisions kept when RevisionHistoryLimit is zero.
+const DefaultRevisionHistoryLimit = 10
+
+// Strategy controls how replicas are replaced during a rolling update.
+type Strategy struct {
+	// MaxSurge is how many replicas may exist above the desired count.
+	MaxSurge int
+	// MaxUnavailable is how many replicas may be not ready below the desired count.
+	MaxUnavailable int
+}
+
+// Validate checks that the limits of the strategy are not negative.
+func (s Strategy) Validate() error {
+	if s.MaxSurge < 0 || s.MaxUnavailable < 0 {
+		return errors.New("maxSurge and maxUnavailable must not be negative")
+	}
+	return nil
+}
+
+// Limits returns the surge and unavailability limits. When both are zero the
+// update could never progress, so a surge of one is used.
+func (s Strategy) Limits() (surge int, unavailable int) {
+	if s.MaxSurge == 0 && s.MaxUnavailable == 0 {
+		return 1, 0
+	}
+	return s.MaxSurge, s.MaxUnavailable
+}
+
+// Revision is a template that the service ran at some point.
+type Revision struct {
+	Number    int
+	Template  task.Task
+	CreatedAt time.Time
+}
+
+// RolloutState is the progress of replacing the replicas of older revisions.
+type RolloutState string
+
+const (
+	RolloutComplete    RolloutState = "Complete"
+	RolloutProgressing RolloutState = "Progressing"
+	// RolloutPaused means a replica of the new revision failed; the rollout does not
+	// progress until it is resumed or rolled back.
+	RolloutPaused RolloutState = "Paused"
+)
+
+// Rollout describes the state of the latest rolling update.
+type Rollout struct {
+	State  RolloutState
+	Reason string `json:",omitempty"`
+}
+
+// Update makes template the current template as a new revision and starts
+// rolling the replicas over to it.
+//
+// Parameters:
+//   - template: The new task template
+//
+// Returns:
+//   - error: If the template is invalid
+func (s *Service) Update(template task.Task) error {
+	if err := validateTemplate(template); err != nil {
+		return err
+	}
+
+	s.Template = template
+	s.record()
+	s.Rollout = Rollout{State: RolloutProgressing}
+	return nil
+}
+
+// Rollback rolls the service back to the template of an earlier revision, which
+// becomes a new revision.
+//
+// Parameters:
+//   - revision: The revision to restore; zero means the one before the current one
+//
+// Returns:
+//   - error: If the revision is not in the history
+func (s *Service) Rollback(revision int) error {
+	if revision == 0 {
+		revision = s.Revision - 1
+	}
+	if revision == s.Revision {
+		return fmt.Errorf("revision %d is the current revision", revision)
+	}
+
+	for _, r := range s.History {
+		if r.Number == revision {
+			return s.Update(r.Template)
+		}
+	}
+	return fmt.Errorf("revision %d not found in history", revision)
+}
+
+// Pause stops the rollout from progressing.
+func (s *Service) Pause(reason string) {
+	s.Rollout = Rollout{State: RolloutPaused, Reason: reason}
+}
+
+// Resume lets a paused rollout progress again.
+func (s *Service) Resume() {
+	if s.Rollout.State == RolloutPaused {
+		s.Rollout = Rollout{State: RolloutProgressing}
+	}
+}
+
+// record appends the current template to the history as the next revision and
+// trims the history to RevisionHistoryLimit.
+func (s *Service) record() {
+	s.Revision++
+	s.History = append(s.History, Revision{
+		Number:    s.Revision,
+		Template:  s.Template,
+		CreatedAt: time.Now().UTC(),
+	})
+
+	limit := s.RevisionHistoryLimit
+	if limit == 0 {
+		limit = DefaultRevisionHistoryLimit
+	}
+	if len(s.History) > limit {
+		s.History = s.History[len(s.History)-limit:]
+	}
+}
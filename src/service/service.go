@@ -0,0 +1,127 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/handler"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// Service keeps a number of identical, long-running tasks running from a template.
+type Service struct {
+	ID       uuid.UUID
+	Name     string
+	Template task.Task
+	Replicas int
+	Strategy Strategy
+	// Revision is the number of the revision whose template is current.
+	Revision int
+	// History holds the most recent revisions, oldest first, including the current one.
+	History              []Revision
+	RevisionHistoryLimit int `json:",omitempty"`
+	Rollout              Rollout
+	// Autoscaling, if set, adjusts Replicas from the resource usage of the tasks.
+	Autoscaling *Autoscaling `json:",omitempty"`
+	// Tasks holds the tasks that currently belong to the service.
+	Tasks     []Replica
+	CreatedAt time.Time
+}
+
+// Replica is a task of a service and the revision of the template it was created from.
+type Replica struct {
+	TaskID   uuid.UUID
+	Revision int
+}
+
+// New creates a service with a new ID after validating it.
+//
+// Parameters:
+//   - svc: The service as submitted
+//
+// Returns:
+//   - *Service: The service at its first revision, with no tasks yet
+//   - error: If the name, template, replica count or strategy is invalid
+func New(svc Service) (*Service, error) {
+	if svc.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if err := validateTemplate(svc.Template); err != nil {
+		return nil, err
+	}
+	if svc.Replicas < 0 {
+		return nil, fmt.Errorf("invalid replica count %d", svc.Replicas)
+	}
+	if err := svc.Strategy.Validate(); err != nil {
+		return nil, err
+	}
+	if svc.Autoscaling != nil {
+		if err := svc.Autoscaling.Validate(); err != nil {
+			return nil, err
+		}
+		svc.Autoscaling.Recommendations = nil
+		svc.Replicas = min(max(svc.Replicas, svc.Autoscaling.MinReplicas), svc.Autoscaling.MaxReplicas)
+	}
+
+	svc.ID = uuid.New()
+	svc.Tasks = nil
+	svc.Revision = 0
+	svc.History = nil
+	svc.CreatedAt = time.Now().UTC()
+	svc.record()
+	svc.Rollout = Rollout{State: RolloutComplete}
+	return &svc, nil
+}
+
+// NewTask returns a new replica built from the current template. Each replica
+// gets a unique container name derived from the service name.
+func (s *Service) NewTask() task.Task {
+	t := s.Template
+	t.ID = uuid.New()
+	t.Kind = task.KindService
+	t.State = task.Pending
+	t.Inputs = nil
+	t.Name = fmt.Sprintf("%s-%s", s.Name, t.ID.String()[:8])
+	return t
+}
+
+// Add records a task created from the current template.
+func (s *Service) Add(id uuid.UUID) {
+	s.Tasks = append(s.Tasks, Replica{TaskID: id, Revision: s.Revision})
+}
+
+// Remove drops a task from the service.
+func (s *Service) Remove(id uuid.UUID) {
+	s.Tasks = slices.DeleteFunc(s.Tasks, func(r Replica) bool { return r.TaskID == id })
+}
+
+// Split returns the replicas of the current revision and those of older revisions.
+func (s *Service) Split() (current []Replica, old []Replica) {
+	for _, r := range s.Tasks {
+		if r.Revision == s.Revision {
+			current = append(current, r)
+		} else {
+			old = append(old, r)
+		}
+	}
+	return current, old
+}
+
+// validateTemplate checks the template with task.Task.ValidateTemplate and
+// that it is not a job.
+//
+// Returns:
+//   - error: A *task.ValidationError listing the invalid fields, or nil
+func validateTemplate(t task.Task) error {
+	fields := t.ValidateTemplate("Template.")
+	if t.IsJob() {
+		fields = append(fields, handler.FieldError{Field: "Template.Kind", Message: "must not be a job"})
+	}
+	if len(fields) > 0 {
+		return &task.ValidationError{Fields: fields}
+	}
+	return nil
+}
@@ -8,7 +8,8 @@ type Store[k comparable, V any] interface {
 	Put(key k, value V) error
 
 	// Get retrieves the value associated with the given key.
-	// Returns the value and nil error if found, or nil and error if not found.
+	// Returns the value and nil error if found, or nil and an error wrapping
+	// ErrNotFound if not found.
 	Get(key k) (V, error)
 
 	// List returns all values in the store.
@@ -18,6 +19,10 @@ type Store[k comparable, V any] interface {
 	// Count returns the total number of items in the store.
 	// Returns the count and nil error on success, or 0 and error on failure.
 	Count() (int, error)
+
+	// Delete removes the value associated with the given key.
+	// Returns nil if the key was removed or did not exist, or an error on failure.
+	Delete(key k) error
 }
 
 type Type uint
@@ -29,3 +34,11 @@ const (
 func NewStore[k comparable, V any](t Type) Store[k, V] {
 	return NewInMemoryTaskStore[k, V]()
 }
+
+// NewCopyStore creates a store of pointers that keeps and returns copies of the
+// values, so that a value read from it can be modified and Put back without
+// racing with the other readers of the value. The copies are shallow: maps and
+// slices of a value must be replaced rather than modified in place.
+func NewCopyStore[k comparable, V any](t Type) Store[k, *V] {
+	return NewInMemoryCopyStore[k, V]()
+}
@@ -1,11 +1,21 @@
 package store
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 )
 
+// ErrNotFound is returned by Get when the key does not exist.
+var ErrNotFound = errors.New("key does not exist")
+
+// InMemoryTaskStore keeps values in a map. It is safe for concurrent use.
 type InMemoryTaskStore[K comparable, V any] struct {
 	Db map[K]V
+
+	// clone, if set, copies the values stored and returned.
+	clone func(V) V
+	mu    sync.RWMutex
 }
 
 func NewInMemoryTaskStore[K comparable, V any]() *InMemoryTaskStore[K, V] {
@@ -14,28 +24,65 @@ func NewInMemoryTaskStore[K comparable, V any]() *InMemoryTaskStore[K, V] {
 	}
 }
 
+// NewInMemoryCopyStore creates an in-memory store of pointers that stores and
+// returns shallow copies of the values, see NewCopyStore.
+func NewInMemoryCopyStore[K comparable, V any]() *InMemoryTaskStore[K, *V] {
+	return &InMemoryTaskStore[K, *V]{
+		Db: make(map[K]*V),
+		clone: func(v *V) *V {
+			if v == nil {
+				return nil
+			}
+			c := *v
+			return &c
+		},
+	}
+}
+
+func (i *InMemoryTaskStore[K, V]) copy(v V) V {
+	if i.clone == nil {
+		return v
+	}
+	return i.clone(v)
+}
+
 func (i *InMemoryTaskStore[K, V]) Put(key K, value V) error {
-	i.Db[key] = value
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.Db[key] = i.copy(value)
 	return nil
 }
 
 func (i *InMemoryTaskStore[K, V]) Get(key K) (V, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
 	value, ok := i.Db[key]
 	if !ok {
 		var zero V
-		return zero, fmt.Errorf("key %v does not exist", key)
+		return zero, fmt.Errorf("key %v: %w", key, ErrNotFound)
 	}
-	return value, nil
+	return i.copy(value), nil
 }
 
 func (i *InMemoryTaskStore[K, V]) List() ([]V, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
 	items := make([]V, 0, len(i.Db))
 	for _, v := range i.Db {
-		items = append(items, v)
+		items = append(items, i.copy(v))
 	}
 	return items, nil
 }
 
 func (i *InMemoryTaskStore[K, V]) Count() (int, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
 	return len(i.Db), nil
 }
+
+func (i *InMemoryTaskStore[K, V]) Delete(key K) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.Db, key)
+	return nil
+}
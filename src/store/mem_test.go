@@ -0,0 +1,39 @@
+package store
+
+import "testing"
+
+type item struct{ State string }
+
+func TestCopyStore(t *testing.T) {
+	s := NewCopyStore[string, item](InMemoryStoreType)
+
+	put := &item{State: "Pending"}
+	if err := s.Put("a", put); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	put.State = "Running"
+
+	got, err := s.Get("a")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.State != "Pending" {
+		t.Errorf("State = %q after modifying the value put, want %q", got.State, "Pending")
+	}
+
+	got.State = "Failed"
+	list, err := s.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(list) != 1 || list[0].State != "Pending" {
+		t.Errorf("List() = %v after modifying a value got, want one Pending item", list)
+	}
+
+	if err := s.Put("a", got); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if got, _ := s.Get("a"); got.State != "Failed" {
+		t.Errorf("State = %q after putting the modified value back, want %q", got.State, "Failed")
+	}
+}
@@ -0,0 +1,145 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+// DockerClient is a long-lived connection to the Docker daemon that is shared by
+// every container operation a worker performs. The underlying API client can be
+// swapped out by Reconnect when the daemon becomes unreachable.
+type DockerClient struct {
+	Timeouts Timeouts
+
+	mu  sync.RWMutex
+	cli *client.Client
+	// retired holds the clients replaced by Reconnect. Operations started
+	// before the reconnect, such as a followed log stream, may still use them,
+	// so they are only closed by Close.
+	retired []*client.Client
+}
+
+// Timeouts bounds how long each Docker operation may take. A zero value means
+// the operation is only bounded by the caller's context.
+type Timeouts struct {
+	Pull   time.Duration
+	Create time.Duration
+	Start  time.Duration
+	Stop   time.Duration
+}
+
+// DefaultTimeouts returns the timeouts used by a newly created DockerClient.
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		Pull:   5 * time.Minute,
+		Create: 30 * time.Second,
+		Start:  30 * time.Second,
+		Stop:   time.Minute,
+	}
+}
+
+// NewDockerClient creates a DockerClient configured from the environment
+// (DOCKER_HOST, DOCKER_API_VERSION, ...) with API version negotiation enabled.
+//
+// Returns:
+//   - *DockerClient: The shared client
+//   - error: If the underlying Docker API client cannot be created
+func NewDockerClient() (*DockerClient, error) {
+	cli, err := newAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return &DockerClient{Timeouts: DefaultTimeouts(), cli: cli}, nil
+}
+
+func newAPIClient() (*client.Client, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docker client: %w", err)
+	}
+	return cli, nil
+}
+
+// API returns the current Docker API client.
+func (c *DockerClient) API() *client.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cli
+}
+
+// Ping checks that the Docker daemon is reachable.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the request
+//
+// Returns:
+//   - error: If the daemon cannot be reached
+func (c *DockerClient) Ping(ctx context.Context) error {
+	_, err := c.API().Ping(ctx)
+	return err
+}
+
+// Reconnect replaces the underlying API client with a freshly created one. The
+// old client is not closed, since operations that obtained it through API may
+// still be using it; it is closed by Close.
+//
+// Returns:
+//   - error: If a new API client cannot be created; the old client is kept in that case
+func (c *DockerClient) Reconnect() error {
+	cli, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cli != nil {
+		c.retired = append(c.retired, c.cli)
+	}
+	c.cli = cli
+	return nil
+}
+
+// EnsureConnected pings the daemon and reconnects once if the ping fails.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the health check
+//
+// Returns:
+//   - error: If the daemon is still unreachable after reconnecting
+func (c *DockerClient) EnsureConnected(ctx context.Context) error {
+	err := c.Ping(ctx)
+	if err == nil {
+		return nil
+	}
+
+	slog.Warn("Docker daemon unreachable, reconnecting", "error", err)
+	if err := c.Reconnect(); err != nil {
+		return err
+	}
+
+	if err := c.Ping(ctx); err != nil {
+		return fmt.Errorf("docker daemon unreachable after reconnect: %w", err)
+	}
+	return nil
+}
+
+// Close releases the resources held by the underlying API client and by the
+// clients it replaced.
+func (c *DockerClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	errs := make([]error, 0, len(c.retired)+1)
+	for _, old := range c.retired {
+		errs = append(errs, old.Close())
+	}
+	c.retired = nil
+	errs = append(errs, c.cli.Close())
+	return errors.Join(errs...)
+}
@@ -3,21 +3,29 @@ package task
 import (
 	"context"
 	"io"
-	"log"
+	"log/slog"
 	"math"
 	"os"
+	"time"
 
 	"github.com/docker/docker/api/types"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
-	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/utkarsh5026/Orchestra/logging"
+	"github.com/utkarsh5026/Orchestra/metrics"
+	"github.com/utkarsh5026/Orchestra/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/utkarsh5026/Orchestra/task")
+
 type Docker struct {
 	Config Config
-	Client *client.Client
+	Client *DockerClient
 }
 
 type DockerResult struct {
@@ -25,6 +33,7 @@ type DockerResult struct {
 	Action      string
 	ContainerId string
 	Result      string
+	StopOutcome StopOutcome
 }
 
 type DockerInspectResponse struct {
@@ -32,29 +41,28 @@ type DockerInspectResponse struct {
 	Inspect types.ContainerJSON
 }
 
-func NewDocker(config Config) (*Docker, error) {
-	c, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
-	if err != nil {
-		log.Fatalf("Error creating Docker c: %v\n", err)
-		return nil, err
-	}
-	return &Docker{Config: config, Client: c}, nil
+// NewDocker creates a Docker runner for the given container configuration that
+// issues its requests through the shared client c.
+func NewDocker(config Config, c *DockerClient) *Docker {
+	return &Docker{Config: config, Client: c}
 }
 
-func (d *Docker) Run() DockerResult {
-	ctx := context.Background()
-	img := d.Config.Image
-	reader, err := d.Client.ImagePull(ctx,
-		img, image.PullOptions{})
-
-	if err != nil {
-		log.Printf("Error pulling image %s: %v\n", img, err)
-		return DockerResult{Error: err}
-	}
-
-	_, err = io.Copy(os.Stdout, reader)
-	if err != nil {
-		log.Printf("Error copying image pull response: %v\n", err)
+// Run makes the configured image available according to its pull policy, then
+// creates and starts a container from it.
+// Each step is bounded by the corresponding timeout of the shared client and
+// aborts as soon as ctx is cancelled.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the whole operation
+//
+// Returns:
+//   - DockerResult with the ID of the started container, or the error that
+//     stopped it and the ID of the container if it was already created
+func (d *Docker) Run(ctx context.Context) DockerResult {
+	cli := d.Client.API()
+	timeouts := d.Client.Timeouts
+
+	if err := d.ensureImage(ctx); err != nil {
 		return DockerResult{Error: err}
 	}
 
@@ -73,39 +81,49 @@ func (d *Docker) Run() DockerResult {
 		ExposedPorts: d.Config.ExposedPorts,
 		Cmd:          d.Config.Cmd,
 		Tty:          false,
+		StopSignal:   d.Config.StopSignal,
 	}
 
 	hc := container.HostConfig{
 		RestartPolicy:   resPo,
 		Resources:       resource,
 		PublishAllPorts: true,
+		Binds:           d.Config.Binds,
 	}
 
-	resp, err := d.Client.ContainerCreate(ctx, &cc, &hc, nil, nil, d.Config.Name)
+	createCtx, cancel := withTimeout(ctx, timeouts.Create)
+	createCtx, done := d.observe(createCtx, "create")
+	resp, err := cli.ContainerCreate(createCtx, &cc, &hc, nil, nil, d.Config.Name)
+	done(err)
+	cancel()
 	if err != nil {
-		log.Printf("Error creating container: %v\n", err)
+		d.logger().Error("Error creating container", "error", err)
 		return DockerResult{Error: err}
 	}
 
-	err = d.Client.ContainerStart(ctx, resp.ID, container.StartOptions{})
+	startCtx, cancel := withTimeout(ctx, timeouts.Start)
+	startCtx, done = d.observe(startCtx, "start")
+	err = cli.ContainerStart(startCtx, resp.ID, container.StartOptions{})
+	done(err)
+	cancel()
 	if err != nil {
-		log.Printf("Error starting container: %v\n", err)
-		return DockerResult{Error: err}
+		d.logger().Error("Error starting container", logging.ContainerID, resp.ID, "error", err)
+		return DockerResult{ContainerId: resp.ID, Error: err}
 	}
 
 	d.Config.Runtime.ContainerId = resp.ID
-	out, err := d.Client.ContainerLogs(ctx, resp.ID,
+	out, err := cli.ContainerLogs(ctx, resp.ID,
 		container.LogsOptions{ShowStdout: true, ShowStderr: true})
 
 	if err != nil {
-		log.Printf("Error getting container logs: %v\n", err)
-		return DockerResult{Error: err}
+		d.logger().Error("Error getting container logs", logging.ContainerID, resp.ID, "error", err)
+		return DockerResult{ContainerId: resp.ID, Error: err}
 	}
 
 	_, err = stdcopy.StdCopy(os.Stdout, os.Stderr, out)
 	if err != nil {
-		log.Printf("Error copying container logs: %v\n", err)
-		return DockerResult{Error: err}
+		d.logger().Error("Error copying container logs", logging.ContainerID, resp.ID, "error", err)
+		return DockerResult{ContainerId: resp.ID, Error: err}
 	}
 
 	return DockerResult{ContainerId: resp.ID,
@@ -113,50 +131,122 @@ func (d *Docker) Run() DockerResult {
 		Result: "success"}
 }
 
-func (d *Docker) Stop(cid string) DockerResult {
-	log.Printf("Stopping container %s\n", cid)
-	ctx := context.Background()
-	err := d.Client.ContainerStop(ctx, cid, container.StopOptions{})
-
+// Stop runs the configured pre-stop hook, sends the stop signal to the container
+// cid and kills it if it has not exited when the grace period expires. The
+// container is then removed, giving up once the client's stop timeout elapses or
+// ctx is cancelled.
+func (d *Docker) Stop(ctx context.Context, cid string) DockerResult {
+	log := d.logger().With(logging.ContainerID, cid)
+	log.Info("Stopping container")
+	stopCtx, done := d.observe(ctx, "stop")
+	outcome, err := d.gracefulStop(stopCtx, cid)
+	done(err)
 	if err != nil {
-		log.Printf("Error stopping container: %v\n", err)
+		log.Error("Error stopping container", "error", err)
 		return DockerResult{Error: err}
 	}
+	log.Info("Container stopped", "outcome", outcome)
+
+	ctx, cancel := withTimeout(ctx, d.Client.Timeouts.Stop)
+	defer cancel()
 
-	err = d.Client.ContainerRemove(ctx, cid, container.RemoveOptions{
+	cli := d.Client.API()
+	ctx, done = d.observe(ctx, "remove")
+	err = cli.ContainerRemove(ctx, cid, container.RemoveOptions{
 		Force:         false,
 		RemoveLinks:   true,
 		RemoveVolumes: true,
 	})
+	done(err)
 
 	if err != nil {
-		log.Printf("Error removing container: %v\n", err)
+		log.Error("Error removing container", "error", err)
 		return DockerResult{Error: err}
 	}
 
 	return DockerResult{ContainerId: cid,
-		Action: "stop",
-		Result: "success"}
+		Action:      "stop",
+		Result:      "success",
+		StopOutcome: outcome}
 }
 
-func (d *Docker) Inspect(cid string) DockerInspectResponse {
-	ctx := context.Background()
-	inspect, err := d.Client.ContainerInspect(ctx, cid)
+func (d *Docker) Inspect(ctx context.Context, cid string) DockerInspectResponse {
+	cli := d.Client.API()
+	ctx, done := d.observe(ctx, "inspect")
+	inspect, err := cli.ContainerInspect(ctx, cid)
+	done(err)
 	if err != nil {
-		log.Printf("Error inspecting container %s: %v\n", cid, err)
+		d.logger().Error("Error inspecting container", logging.ContainerID, cid, "error", err)
 		return DockerInspectResponse{Error: err}
 	}
 	return DockerInspectResponse{Inspect: inspect}
 }
 
-func (d *Docker) Remove(cid string) DockerResult {
-	ctx := context.Background()
-	err := d.Client.ContainerRemove(ctx, cid, container.RemoveOptions{})
+// Remove removes the container cid, killing it first if it is still running.
+func (d *Docker) Remove(ctx context.Context, cid string) DockerResult {
+	cli := d.Client.API()
+	ctx, done := d.observe(ctx, "remove")
+	err := cli.ContainerRemove(ctx, cid, container.RemoveOptions{Force: true})
+	done(err)
 
 	if err != nil {
-		log.Printf("Error removing container: %v\n", err)
+		d.logger().Error("Error removing container", logging.ContainerID, cid, "error", err)
 		return DockerResult{Error: err}
 	}
 
 	return DockerResult{ContainerId: cid, Action: "remove", Result: "success"}
 }
+
+// pull downloads the configured image and discards the progress stream, bounded by
+// the given timeout.
+func (d *Docker) pull(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := withTimeout(ctx, timeout)
+	defer cancel()
+
+	img := d.Config.Image
+	ctx, done := d.observe(ctx, "pull")
+	reader, err := d.Client.API().ImagePull(ctx, img, image.PullOptions{RegistryAuth: d.Config.RegistryAuth})
+	if err != nil {
+		done(err)
+		d.logger().Error("Error pulling image", "error", err)
+		return err
+	}
+	defer reader.Close()
+
+	_, err = io.Copy(io.Discard, reader)
+	done(err)
+	if err != nil {
+		d.logger().Error("Error reading image pull response", "error", err)
+		return err
+	}
+	d.logger().Info("Pulled image")
+	return nil
+}
+
+// withTimeout derives a context from ctx that expires after timeout. A zero
+// timeout leaves the deadline of ctx unchanged.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// observe starts a span for a Docker operation on ctx. The returned function
+// ends the span and records the duration of the operation in the metrics.
+func (d *Docker) observe(ctx context.Context, operation string) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, "docker."+operation,
+		trace.WithAttributes(attribute.String("container.image.name", d.Config.Image)))
+
+	return ctx, func(err error) {
+		metrics.ObserveDockerOperation(operation, start, err)
+		tracing.RecordError(span, err)
+		span.End()
+	}
+}
+
+// logger returns the default logger with the task and image of the container attached.
+func (d *Docker) logger() *slog.Logger {
+	return slog.With(logging.TaskID, d.Config.TaskID, logging.Image, d.Config.Image)
+}
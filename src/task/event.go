@@ -1,6 +1,7 @@
 package task
 
 import (
+	"github.com/docker/docker/api/types/registry"
 	"github.com/google/uuid"
 	"time"
 )
@@ -10,4 +11,10 @@ type Event struct {
 	State     State
 	Timestamp time.Time
 	Task      Task
+	// RegistryAuth carries the credentials of the task's ImagePullSecret from the
+	// manager to the worker. It is never persisted in the event store.
+	RegistryAuth *registry.AuthConfig `json:",omitempty"`
+	// TraceContext carries the trace of the request that queued the event, so
+	// that the spans of its dispatch join the same trace.
+	TraceContext map[string]string `json:",omitempty"`
 }
@@ -0,0 +1,43 @@
+package task
+
+import (
+	"context"
+	"time"
+)
+
+// HealthCheckTimeout bounds a single run of a task's health check.
+const HealthCheckTimeout = 10 * time.Second
+
+// Health is the result of the latest health check of a running task.
+type Health string
+
+const (
+	Healthy   Health = "Healthy"
+	Unhealthy Health = "Unhealthy"
+)
+
+// Probe runs the health check h against the container cid.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the check
+//   - cid: The ID of the running container
+//   - h: The health check to run
+//
+// Returns:
+//   - Health: Healthy if the check succeeded, Unhealthy otherwise
+//   - error: The reason the check failed, if it did
+func (d *Docker) Probe(ctx context.Context, cid string, h Hook) (Health, error) {
+	ctx, cancel := context.WithTimeout(ctx, HealthCheckTimeout)
+	defer cancel()
+
+	if err := d.runHook(ctx, cid, h); err != nil {
+		return Unhealthy, err
+	}
+	return Healthy, nil
+}
+
+// IsReady reports whether t is running and has passed its health check, if it
+// declares one.
+func (t *Task) IsReady() bool {
+	return t.State == Running && (t.HealthCheck == nil || t.Health == Healthy)
+}
@@ -0,0 +1,68 @@
+package task
+
+import "time"
+
+// Kind distinguishes long-running services from run-to-completion jobs.
+type Kind string
+
+const (
+	// KindService is a long-running task; its container exiting is a failure. This is the default.
+	KindService Kind = "Service"
+	// KindJob is a task that runs to completion; it is Completed when its container
+	// exits with code 0 and Failed otherwise.
+	KindJob Kind = "Job"
+)
+
+// Reasons recorded on a task when it fails for a reason other than its exit code.
+const (
+	ReasonDeadlineExceeded = "DeadlineExceeded"
+	ReasonOutputsFailed    = "OutputsFailed"
+	ReasonWorkerLost       = "WorkerLost"
+	ReasonImagePullSecret  = "ImagePullSecretNotFound"
+)
+
+// ReasonCancelled is recorded on a task that was stopped before it was sent to a
+// worker; it is Completed without having run.
+const ReasonCancelled = "Cancelled"
+
+// IsJob reports whether t runs to completion.
+func (t *Task) IsJob() bool {
+	return t.Kind == KindJob
+}
+
+// IsFinished reports whether t has reached a terminal state.
+func (t *Task) IsFinished() bool {
+	return t.State == Completed || t.State == Failed
+}
+
+// ExitState returns the state a task moves to when its container exits with the
+// given code.
+func (t *Task) ExitState(exitCode int) State {
+	if t.IsJob() && exitCode == 0 {
+		return Completed
+	}
+	return Failed
+}
+
+// DeadlineExceeded reports whether a running job has been active for longer than
+// its ActiveDeadline. The deadline applies to each attempt separately.
+func (t *Task) DeadlineExceeded(now time.Time) bool {
+	if !t.IsJob() || t.ActiveDeadline <= 0 || t.State != Running {
+		return false
+	}
+	return now.After(t.StartTime.Add(t.ActiveDeadline))
+}
+
+// Expired reports whether a finished job has outlived its TTLAfterFinished and
+// should be cleaned up.
+func (t *Task) Expired(now time.Time) bool {
+	if !t.IsJob() || t.TTLAfterFinished <= 0 || !t.IsFinished() {
+		return false
+	}
+	return now.After(t.EndTime.Add(t.TTLAfterFinished))
+}
+
+// CanRetry reports whether a failed job has retries left under its BackoffLimit.
+func (t *Task) CanRetry() bool {
+	return t.IsJob() && t.State == Failed && t.Attempts < t.BackoffLimit
+}
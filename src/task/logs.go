@@ -0,0 +1,42 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// LogOptions selects which output of a container is returned by Logs.
+type LogOptions struct {
+	// Follow keeps the stream open and sends new output as it is written.
+	Follow bool
+	// Tail is the number of lines to return from the end of the output; empty or
+	// "all" returns all of it.
+	Tail string
+}
+
+// Logs returns the multiplexed stdout and stderr stream of the container cid, as
+// written by the Docker daemon; use stdcopy.StdCopy to split it.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the stream
+//   - cid: The ID of the container, which may have exited
+//   - opts: Which part of the output to return
+//
+// Returns:
+//   - io.ReadCloser: The log stream; the caller must close it
+//   - error: If the logs cannot be read
+func (d *Docker) Logs(ctx context.Context, cid string, opts LogOptions) (io.ReadCloser, error) {
+	rc, err := d.Client.API().ContainerLogs(ctx, cid, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     opts.Follow,
+		Tail:       opts.Tail,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs of container %s: %w", cid, err)
+	}
+	return rc, nil
+}
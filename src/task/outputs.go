@@ -0,0 +1,160 @@
+package task
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// maxOutputsSize bounds the size of the key/value outputs file read from a container.
+const maxOutputsSize = 64 * 1024
+
+// Artifact is a file or directory copied out of a job's container when it completes.
+type Artifact struct {
+	Name string
+	// Path is the absolute path of the file or directory inside the container.
+	Path string
+}
+
+// InputsDir is the directory of the container under which input artifacts are
+// mounted.
+const InputsDir = "/inputs"
+
+// ArtifactInput is an artifact of an upstream task mounted read-only into the
+// container of a dependent task. Inputs are set by the manager from the
+// upstream steps of a workflow; they are never accepted from clients.
+type ArtifactInput struct {
+	TaskID uuid.UUID
+	Name   string
+	// Worker is the address of the worker holding the artifact.
+	Worker string
+	// MountPath is the absolute path under InputsDir the artifact is mounted at.
+	MountPath string
+}
+
+// CopyFrom returns a tar stream of the file or directory at path inside the container cid.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the request
+//   - cid: The ID of the container, which may have exited
+//   - path: The absolute path inside the container
+//
+// Returns:
+//   - io.ReadCloser: The tar stream; the caller must close it
+//   - error: If the path cannot be copied
+func (d *Docker) CopyFrom(ctx context.Context, cid string, path string) (io.ReadCloser, error) {
+	rc, _, err := d.Client.API().CopyFromContainer(ctx, cid, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy %s from container %s: %w", path, cid, err)
+	}
+	return rc, nil
+}
+
+// ReadOutputs parses the first file of a tar stream as KEY=VALUE lines. Blank
+// lines and lines starting with # are ignored.
+//
+// Parameters:
+//   - r: A tar stream as returned by CopyFrom
+//
+// Returns:
+//   - map[string]string: The parsed outputs
+//   - error: If the stream contains no file or a line is malformed
+func ReadOutputs(r io.Reader) (map[string]string, error) {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("outputs file not found")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read outputs: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			break
+		}
+	}
+
+	outputs := make(map[string]string)
+	scanner := bufio.NewScanner(io.LimitReader(tr, maxOutputsSize))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("malformed output line %q", line)
+		}
+		outputs[strings.TrimSpace(key)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read outputs: %w", err)
+	}
+	return outputs, nil
+}
+
+// ExtractTar extracts a tar stream into dir, rejecting entries that would be
+// written outside of it.
+//
+// Parameters:
+//   - r: The tar stream
+//   - dir: The destination directory, created if missing
+//
+// Returns:
+//   - error: If an entry is invalid or cannot be written
+func ExtractTar(r io.Reader, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		target := filepath.Join(dir, hdr.Name)
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid archive entry %q", hdr.Name)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tr, os.FileMode(hdr.Mode).Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
@@ -0,0 +1,89 @@
+package task
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/client"
+)
+
+// PullPolicy determines when a worker pulls the image of a task before starting it.
+type PullPolicy string
+
+const (
+	// PullAlways pulls the image every time the task is started. This is the default.
+	PullAlways PullPolicy = "Always"
+	// PullIfNotPresent only pulls the image if it is not already present on the worker.
+	PullIfNotPresent PullPolicy = "IfNotPresent"
+	// PullNever never pulls the image and fails the task if it is not present.
+	PullNever PullPolicy = "Never"
+)
+
+// IsValid reports whether p is a known pull policy. The empty policy is valid
+// and behaves like PullAlways.
+func (p PullPolicy) IsValid() bool {
+	switch p {
+	case "", PullAlways, PullIfNotPresent, PullNever:
+		return true
+	}
+	return false
+}
+
+// RegistryHost returns the registry domain of an image reference, e.g.
+// "docker.io" for "nginx:latest" or "ghcr.io" for "ghcr.io/org/app:1.0".
+//
+// Parameters:
+//   - img: The image reference
+//
+// Returns:
+//   - string: The registry domain
+//   - error: If img is not a valid image reference
+func RegistryHost(img string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(img)
+	if err != nil {
+		return "", fmt.Errorf("invalid image reference %q: %w", img, err)
+	}
+	return reference.Domain(named), nil
+}
+
+// EncodeRegistryAuth encodes credentials in the form expected by the Docker API's
+// RegistryAuth option.
+func EncodeRegistryAuth(auth registry.AuthConfig) (string, error) {
+	return registry.EncodeAuthConfig(auth)
+}
+
+// ensureImage makes the configured image available locally according to the
+// configured pull policy.
+func (d *Docker) ensureImage(ctx context.Context) error {
+	img := d.Config.Image
+
+	switch d.Config.PullPolicy {
+	case PullNever, PullIfNotPresent:
+		present, err := d.imagePresent(ctx, img)
+		if err != nil {
+			return err
+		}
+		if present {
+			d.logger().Debug("Image already present, not pulling")
+			return nil
+		}
+		if d.Config.PullPolicy == PullNever {
+			return fmt.Errorf("image %s is not present and pull policy is %s", img, PullNever)
+		}
+	}
+
+	return d.pull(ctx, d.Client.Timeouts.Pull)
+}
+
+func (d *Docker) imagePresent(ctx context.Context, img string) (bool, error) {
+	_, _, err := d.Client.API().ImageInspectWithRaw(ctx, img)
+	if err == nil {
+		return true, nil
+	}
+	if client.IsErrNotFound(err) {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to inspect image %s: %w", img, err)
+}
@@ -0,0 +1,290 @@
+package task
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultPageSize is the number of tasks in a page when Query.Limit is zero.
+	DefaultPageSize = 100
+	// MaxPageSize caps Query.Limit.
+	MaxPageSize = 1000
+)
+
+// SortField is a field tasks can be sorted by.
+type SortField string
+
+const (
+	SortByStartTime SortField = "start_time"
+	SortByEndTime   SortField = "end_time"
+	SortByName      SortField = "name"
+	SortByState     SortField = "state"
+	SortByID        SortField = "id"
+)
+
+// Query selects, orders and pages a list of tasks.
+type Query struct {
+	// States, if not empty, only selects tasks in one of these states.
+	States []State
+	// Selector only selects tasks whose labels match it.
+	Selector Selector
+	// NamePrefix only selects tasks whose name starts with it.
+	NamePrefix string
+	// StartedAfter, StartedBefore, FinishedAfter and FinishedBefore bound the
+	// StartTime and EndTime of the selected tasks; zero values are unbounded.
+	StartedAfter   time.Time
+	StartedBefore  time.Time
+	FinishedAfter  time.Time
+	FinishedBefore time.Time
+	// Sort is the field tasks are ordered by; ties are broken by ID.
+	Sort SortField
+	// Desc orders the tasks from the greatest value of Sort to the smallest.
+	Desc bool
+	// Limit is the maximum number of tasks in a page; zero means DefaultPageSize.
+	// It is capped at MaxPageSize.
+	Limit int
+	// Cursor continues after the last task of a previous page, as returned by Page.
+	Cursor string
+}
+
+// ParseQuery reads a query from URL parameters:
+//
+//	state            a state name; may be repeated or comma-separated
+//	selector         a label selector, see ParseSelector
+//	name_prefix      a prefix of the task name
+//	started_after, started_before, finished_after, finished_before
+//	                 RFC 3339 times bounding StartTime and EndTime
+//	sort             start_time (the default), end_time, name, state or id
+//	order            asc or desc (the default for start_time and end_time)
+//	limit            the page size, at most MaxPageSize
+//	cursor           the cursor of the next page returned with the previous one
+func ParseQuery(v url.Values) (Query, error) {
+	var q Query
+	for _, param := range v["state"] {
+		for _, name := range strings.Split(param, ",") {
+			s, err := ParseState(strings.TrimSpace(name))
+			if err != nil {
+				return Query{}, err
+			}
+			q.States = append(q.States, s)
+		}
+	}
+
+	sel, err := ParseSelector(v.Get("selector"))
+	if err != nil {
+		return Query{}, err
+	}
+	q.Selector = sel
+	q.NamePrefix = v.Get("name_prefix")
+
+	for param, t := range map[string]*time.Time{
+		"started_after":   &q.StartedAfter,
+		"started_before":  &q.StartedBefore,
+		"finished_after":  &q.FinishedAfter,
+		"finished_before": &q.FinishedBefore,
+	} {
+		if s := v.Get(param); s != "" {
+			if *t, err = time.Parse(time.RFC3339, s); err != nil {
+				return Query{}, fmt.Errorf("invalid %s: %w", param, err)
+			}
+		}
+	}
+
+	q.Sort = SortField(v.Get("sort"))
+	switch q.Sort {
+	case "":
+		q.Sort = SortByStartTime
+	case SortByStartTime, SortByEndTime, SortByName, SortByState, SortByID:
+	default:
+		return Query{}, fmt.Errorf("invalid sort field %q", q.Sort)
+	}
+
+	switch order := v.Get("order"); order {
+	case "":
+		q.Desc = q.Sort == SortByStartTime || q.Sort == SortByEndTime
+	case "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		return Query{}, fmt.Errorf("invalid order %q", order)
+	}
+
+	if s := v.Get("limit"); s != "" {
+		if q.Limit, err = strconv.Atoi(s); err != nil || q.Limit < 0 {
+			return Query{}, fmt.Errorf("invalid limit %q", s)
+		}
+	}
+	q.Cursor = v.Get("cursor")
+	if q.Cursor != "" {
+		if _, err := decodeCursor(q.Cursor); err != nil {
+			return Query{}, err
+		}
+	}
+	return q, nil
+}
+
+// Values returns the URL parameters that ParseQuery reads back into q.
+func (q Query) Values() url.Values {
+	v := url.Values{}
+	for _, s := range q.States {
+		v.Add("state", s.String())
+	}
+	if len(q.Selector) > 0 {
+		v.Set("selector", q.Selector.String())
+	}
+	if q.NamePrefix != "" {
+		v.Set("name_prefix", q.NamePrefix)
+	}
+	for param, t := range map[string]time.Time{
+		"started_after":   q.StartedAfter,
+		"started_before":  q.StartedBefore,
+		"finished_after":  q.FinishedAfter,
+		"finished_before": q.FinishedBefore,
+	} {
+		if !t.IsZero() {
+			v.Set(param, t.Format(time.RFC3339))
+		}
+	}
+	if q.Sort != "" {
+		v.Set("sort", string(q.Sort))
+		if q.Desc {
+			v.Set("order", "desc")
+		} else {
+			v.Set("order", "asc")
+		}
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Cursor != "" {
+		v.Set("cursor", q.Cursor)
+	}
+	return v
+}
+
+// Matches reports whether t is selected by the filters of the query.
+func (q Query) Matches(t *Task) bool {
+	if len(q.States) > 0 && !slices.Contains(q.States, t.State) {
+		return false
+	}
+	if !q.Selector.Matches(t.Labels) || !strings.HasPrefix(t.Name, q.NamePrefix) {
+		return false
+	}
+	if !q.StartedAfter.IsZero() && !t.StartTime.After(q.StartedAfter) {
+		return false
+	}
+	if !q.StartedBefore.IsZero() && (t.StartTime.IsZero() || !t.StartTime.Before(q.StartedBefore)) {
+		return false
+	}
+	if !q.FinishedAfter.IsZero() && !t.EndTime.After(q.FinishedAfter) {
+		return false
+	}
+	if !q.FinishedBefore.IsZero() && (t.EndTime.IsZero() || !t.EndTime.Before(q.FinishedBefore)) {
+		return false
+	}
+	return true
+}
+
+// Page selects the tasks matching the query, orders them and returns the page
+// following the cursor.
+//
+// Parameters:
+//   - tasks: The tasks to select from, in any order
+//
+// Returns:
+//   - []*Task: The tasks of the page
+//   - string: The cursor of the next page; empty if this is the last one
+//   - int: The number of tasks matching the query across all pages
+//   - error: If the cursor is malformed
+func (q Query) Page(tasks []*Task) ([]*Task, string, int, error) {
+	selected := make([]*Task, 0, len(tasks))
+	for _, t := range tasks {
+		if q.Matches(t) {
+			selected = append(selected, t)
+		}
+	}
+	slices.SortFunc(selected, q.compare)
+	total := len(selected)
+
+	if q.Cursor != "" {
+		after, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, "", 0, err
+		}
+		i, _ := slices.BinarySearchFunc(selected, after, q.compare)
+		if i < len(selected) && selected[i].ID == after.ID {
+			i++
+		}
+		selected = selected[i:]
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+	if len(selected) <= limit {
+		return selected, "", total, nil
+	}
+	page := selected[:limit]
+	return page, encodeCursor(page[limit-1]), total, nil
+}
+
+// compare orders tasks by the sort field of the query, then by ID.
+func (q Query) compare(a, b *Task) int {
+	var c int
+	switch q.Sort {
+	case SortByEndTime:
+		c = a.EndTime.Compare(b.EndTime)
+	case SortByName:
+		c = cmp.Compare(a.Name, b.Name)
+	case SortByState:
+		c = cmp.Compare(a.State, b.State)
+	case SortByID:
+	default:
+		c = a.StartTime.Compare(b.StartTime)
+	}
+	if c == 0 {
+		c = cmp.Compare(a.ID.String(), b.ID.String())
+	}
+	if q.Desc {
+		return -c
+	}
+	return c
+}
+
+// cursor holds the sort keys of the last task of a page.
+type cursor struct {
+	ID        uuid.UUID
+	Name      string    `json:",omitempty"`
+	State     State     `json:",omitempty"`
+	StartTime time.Time `json:",omitempty"`
+	EndTime   time.Time `json:",omitempty"`
+}
+
+func encodeCursor(t *Task) string {
+	b, _ := json.Marshal(cursor{ID: t.ID, Name: t.Name, State: t.State, StartTime: t.StartTime, EndTime: t.EndTime})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (*Task, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	var c cursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	return &Task{ID: c.ID, Name: c.Name, State: c.State, StartTime: c.StartTime, EndTime: c.EndTime}, nil
+}
@@ -0,0 +1,177 @@
+package task
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// queryTasks returns tasks with distinct IDs whose names, states and start
+// times repeat, so that ordering by any of them has ties broken by ID.
+func queryTasks() []*Task {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	states := []State{Pending, Scheduled, Running, Completed, Failed}
+	tasks := make([]*Task, 23)
+	for i := range tasks {
+		tasks[i] = &Task{
+			ID:        uuid.New(),
+			Name:      fmt.Sprintf("task-%d", i%4),
+			State:     states[i%len(states)],
+			StartTime: base.Add(time.Duration(i%7) * time.Minute),
+			EndTime:   base.Add(time.Duration(i%3) * time.Hour),
+			Labels:    map[string]string{"app": []string{"web", "db"}[i%2]},
+		}
+	}
+	return tasks
+}
+
+func ids(tasks []*Task) []uuid.UUID {
+	out := make([]uuid.UUID, len(tasks))
+	for i, t := range tasks {
+		out[i] = t.ID
+	}
+	return out
+}
+
+func TestQueryPage(t *testing.T) {
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{name: "default", query: url.Values{"limit": {"5"}}},
+		{name: "by end time ascending", query: url.Values{"sort": {"end_time"}, "order": {"asc"}, "limit": {"4"}}},
+		{name: "by name", query: url.Values{"sort": {"name"}, "limit": {"3"}}},
+		{name: "by state descending", query: url.Values{"sort": {"state"}, "order": {"desc"}, "limit": {"6"}}},
+		{name: "by id", query: url.Values{"sort": {"id"}, "limit": {"7"}}},
+		{name: "filtered", query: url.Values{"state": {"Running,Failed"}, "selector": {"app=web"}, "limit": {"2"}}},
+		{name: "single page", query: url.Values{"limit": {"100"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := queryTasks()
+			q, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+
+			var want []*Task
+			for _, task := range tasks {
+				if q.Matches(task) {
+					want = append(want, task)
+				}
+			}
+			slices.SortFunc(want, q.compare)
+
+			// Page through the tasks, passing each cursor through the URL
+			// parameters as a client would.
+			var got []*Task
+			for pages := 0; ; pages++ {
+				if pages > len(tasks) {
+					t.Fatal("paging did not end")
+				}
+				page, next, total, err := q.Page(tasks)
+				if err != nil {
+					t.Fatalf("Page() error = %v", err)
+				}
+				if total != len(want) {
+					t.Errorf("total = %d, want %d", total, len(want))
+				}
+				if len(page) > q.Limit {
+					t.Fatalf("page has %d tasks, limit is %d", len(page), q.Limit)
+				}
+				got = append(got, page...)
+				if next == "" {
+					break
+				}
+				q.Cursor = next
+				if q, err = ParseQuery(q.Values()); err != nil {
+					t.Fatalf("ParseQuery() of the next page error = %v", err)
+				}
+			}
+
+			if !slices.Equal(ids(got), ids(want)) {
+				t.Errorf("pages = %v, want %v", ids(got), ids(want))
+			}
+		})
+	}
+}
+
+func TestQueryPageAfterLastTaskRemoved(t *testing.T) {
+	tasks := queryTasks()
+	q := Query{Sort: SortByName, Limit: 5}
+	first, next, _, err := q.Page(tasks)
+	if err != nil {
+		t.Fatalf("Page() error = %v", err)
+	}
+
+	// The task the cursor points at is removed before the next page is read.
+	last := first[len(first)-1]
+	tasks = slices.DeleteFunc(tasks, func(t *Task) bool { return t.ID == last.ID })
+	q.Cursor = next
+	second, _, _, err := q.Page(tasks)
+	if err != nil {
+		t.Fatalf("Page() error = %v", err)
+	}
+
+	all := slices.Clone(tasks)
+	slices.SortFunc(all, q.compare)
+	if want := ids(all[len(first)-1 : len(first)-1+q.Limit]); !slices.Equal(ids(second), want) {
+		t.Errorf("second page = %v, want %v", ids(second), want)
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := &Task{
+		ID:        uuid.New(),
+		Name:      "web",
+		State:     Running,
+		StartTime: time.Date(2024, 5, 6, 7, 8, 9, 10, time.UTC),
+		EndTime:   time.Date(2024, 5, 6, 8, 0, 0, 0, time.UTC),
+	}
+	got, err := decodeCursor(encodeCursor(want))
+	if err != nil {
+		t.Fatalf("decodeCursor() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeCursor() = %+v, want %+v", got, want)
+	}
+
+	for _, bad := range []string{"not base64!", "bm90IGpzb24"} {
+		if _, err := decodeCursor(bad); err == nil {
+			t.Errorf("decodeCursor(%q) error = nil, want an error", bad)
+		}
+		if _, err := ParseQuery(url.Values{"cursor": {bad}}); err == nil {
+			t.Errorf("ParseQuery() with cursor %q error = nil, want an error", bad)
+		}
+	}
+}
+
+func TestParseQueryValuesRoundTrip(t *testing.T) {
+	sel, err := ParseSelector("app=web")
+	if err != nil {
+		t.Fatalf("ParseSelector() error = %v", err)
+	}
+	want := Query{
+		States:        []State{Running, Failed},
+		Selector:      sel,
+		NamePrefix:    "web-",
+		StartedAfter:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		FinishedAfter: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		Sort:          SortByEndTime,
+		Limit:         10,
+		Cursor:        encodeCursor(&Task{ID: uuid.New()}),
+	}
+	got, err := ParseQuery(want.Values())
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseQuery(Values()) = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+)
+
+// requirement is a single term of a Selector.
+type requirement struct {
+	key   string
+	value string
+	// op is "=", "!=", "exists" or "!exists".
+	op string
+}
+
+// Selector matches tasks by their labels.
+type Selector []requirement
+
+// ParseSelector parses a comma-separated list of label requirements:
+//
+//	key=value   the label is set to value ("==" is accepted too)
+//	key!=value  the label is not set to value
+//	key         the label is set
+//	!key        the label is not set
+//
+// An empty string parses to a selector that matches every task.
+func ParseSelector(s string) (Selector, error) {
+	var sel Selector
+	if strings.TrimSpace(s) == "" {
+		return sel, nil
+	}
+
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		var r requirement
+		switch {
+		case strings.Contains(term, "!="):
+			k, v, _ := strings.Cut(term, "!=")
+			r = requirement{key: k, value: v, op: "!="}
+		case strings.Contains(term, "=="):
+			k, v, _ := strings.Cut(term, "==")
+			r = requirement{key: k, value: v, op: "="}
+		case strings.Contains(term, "="):
+			k, v, _ := strings.Cut(term, "=")
+			r = requirement{key: k, value: v, op: "="}
+		case strings.HasPrefix(term, "!"):
+			r = requirement{key: term[1:], op: "!exists"}
+		default:
+			r = requirement{key: term, op: "exists"}
+		}
+
+		r.key = strings.TrimSpace(r.key)
+		r.value = strings.TrimSpace(r.value)
+		if r.key == "" || strings.ContainsAny(r.key, "=! ") {
+			return nil, fmt.Errorf("invalid label selector term %q", term)
+		}
+		sel = append(sel, r)
+	}
+	return sel, nil
+}
+
+// Matches reports whether labels satisfy every requirement of the selector.
+func (sel Selector) Matches(labels map[string]string) bool {
+	for _, r := range sel {
+		v, ok := labels[r.key]
+		switch r.op {
+		case "=":
+			if !ok || v != r.value {
+				return false
+			}
+		case "!=":
+			if ok && v == r.value {
+				return false
+			}
+		case "exists":
+			if !ok {
+				return false
+			}
+		case "!exists":
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// String returns the selector in the form read by ParseSelector.
+func (sel Selector) String() string {
+	terms := make([]string, 0, len(sel))
+	for _, r := range sel {
+		switch r.op {
+		case "exists":
+			terms = append(terms, r.key)
+		case "!exists":
+			terms = append(terms, "!"+r.key)
+		default:
+			terms = append(terms, r.key+r.op+r.value)
+		}
+	}
+	return strings.Join(terms, ",")
+}
@@ -1,6 +1,10 @@
 package task
 
-import "slices"
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
 
 type State uint
 
@@ -12,12 +16,41 @@ const (
 	Failed
 )
 
+// States lists every task state in lifecycle order.
+var States = []State{Pending, Scheduled, Running, Completed, Failed}
+
+var stateNames = map[State]string{
+	Pending:   "Pending",
+	Scheduled: "Scheduled",
+	Running:   "Running",
+	Completed: "Completed",
+	Failed:    "Failed",
+}
+
+// String returns the name of the state, e.g. "Running".
+func (s State) String() string {
+	if name, ok := stateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("State(%d)", uint(s))
+}
+
+// ParseState returns the state with the given name, ignoring case.
+func ParseState(name string) (State, error) {
+	for s, n := range stateNames {
+		if strings.EqualFold(n, name) {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown task state %q", name)
+}
+
 var stateTransitionMap = map[State][]State{
 	Pending:   {Scheduled},
 	Scheduled: {Scheduled, Running, Failed},
 	Running:   {Running, Completed, Failed},
 	Completed: {},
-	Failed:    {},
+	Failed:    {Scheduled},
 }
 
 func (s State) CanTransitionTo(next State) bool {
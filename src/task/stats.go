@@ -0,0 +1,176 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/google/uuid"
+)
+
+// Sample is a raw reading of the resources used by a container. CPU, network and
+// block IO counters are cumulative, so utilization is derived from two
+// consecutive samples.
+type Sample struct {
+	Time time.Time
+	// CPUTotal is the CPU time consumed by the container, in nanoseconds.
+	CPUTotal uint64
+	// SystemCPU is the CPU time consumed by the host, in nanoseconds.
+	SystemCPU   uint64
+	OnlineCPUs  uint32
+	MemoryUsage uint64
+	MemoryLimit uint64
+	// NetworkRx and NetworkTx are the bytes received and sent on all interfaces.
+	NetworkRx uint64
+	NetworkTx uint64
+	// BlockRead and BlockWrite are the bytes read from and written to block devices.
+	BlockRead  uint64
+	BlockWrite uint64
+}
+
+// Usage is the resource utilization of a task's container between two samples.
+type Usage struct {
+	Time time.Time
+	// CPUPercent is the CPU used, where 100 is one full CPU.
+	CPUPercent    float64
+	MemoryUsage   uint64
+	MemoryPercent float64
+	// The byte counts are totals since the container started.
+	NetworkRxBytes  uint64
+	NetworkTxBytes  uint64
+	BlockReadBytes  uint64
+	BlockWriteBytes uint64
+}
+
+// UsageSummary condenses a usage history into the figures needed to size the
+// resource requests of a task.
+type UsageSummary struct {
+	Samples          int
+	AvgCPUPercent    float64
+	MaxCPUPercent    float64
+	AvgMemoryUsage   uint64
+	MaxMemoryUsage   uint64
+	AvgMemoryPercent float64
+	MaxMemoryPercent float64
+}
+
+// Stats is the recent usage history of a task, oldest first.
+type Stats struct {
+	TaskID uuid.UUID
+	// Worker is set by the manager to the worker that reported the stats.
+	Worker  string `json:",omitempty"`
+	History []Usage
+	Summary UsageSummary
+}
+
+// NewStats returns the stats of a task with the summary computed from history.
+func NewStats(id uuid.UUID, history []Usage) Stats {
+	return Stats{TaskID: id, History: history, Summary: Summarize(history)}
+}
+
+// Summarize computes the average and peak CPU and memory usage of a history.
+func Summarize(history []Usage) UsageSummary {
+	s := UsageSummary{Samples: len(history)}
+	if len(history) == 0 {
+		return s
+	}
+
+	var cpu, memPercent float64
+	var mem uint64
+	for _, u := range history {
+		cpu += u.CPUPercent
+		mem += u.MemoryUsage
+		memPercent += u.MemoryPercent
+		s.MaxCPUPercent = max(s.MaxCPUPercent, u.CPUPercent)
+		s.MaxMemoryUsage = max(s.MaxMemoryUsage, u.MemoryUsage)
+		s.MaxMemoryPercent = max(s.MaxMemoryPercent, u.MemoryPercent)
+	}
+	n := len(history)
+	s.AvgCPUPercent = cpu / float64(n)
+	s.AvgMemoryUsage = mem / uint64(n)
+	s.AvgMemoryPercent = memPercent / float64(n)
+	return s
+}
+
+// Sample reads the current resource usage of the container cid without waiting
+// for a second reading from the daemon.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the request
+//   - cid: The ID of the running container
+//
+// Returns:
+//   - Sample: The reading
+//   - error: If the stats cannot be read or decoded
+func (d *Docker) Sample(ctx context.Context, cid string) (Sample, error) {
+	resp, err := d.Client.API().ContainerStatsOneShot(ctx, cid)
+	if err != nil {
+		return Sample{}, fmt.Errorf("failed to get stats of container %s: %w", cid, err)
+	}
+	defer resp.Body.Close()
+
+	var stats container.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return Sample{}, fmt.Errorf("failed to decode stats of container %s: %w", cid, err)
+	}
+
+	// Page cache is reclaimable, so it is not counted as used memory.
+	mem := stats.MemoryStats.Usage
+	if cache := stats.MemoryStats.Stats["inactive_file"]; cache < mem {
+		mem -= cache
+	}
+
+	sample := Sample{
+		Time:        stats.Read,
+		CPUTotal:    stats.CPUStats.CPUUsage.TotalUsage,
+		SystemCPU:   stats.CPUStats.SystemUsage,
+		OnlineCPUs:  stats.CPUStats.OnlineCPUs,
+		MemoryUsage: mem,
+		MemoryLimit: stats.MemoryStats.Limit,
+	}
+	for _, n := range stats.Networks {
+		sample.NetworkRx += n.RxBytes
+		sample.NetworkTx += n.TxBytes
+	}
+	for _, b := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(b.Op) {
+		case "read":
+			sample.BlockRead += b.Value
+		case "write":
+			sample.BlockWrite += b.Value
+		}
+	}
+	return sample, nil
+}
+
+// NewUsage computes the utilization between two samples of the same container.
+//
+// Parameters:
+//   - prev: The earlier sample
+//   - cur: The later sample
+//
+// Returns:
+//   - Usage: The utilization at the time of cur
+func NewUsage(prev, cur Sample) Usage {
+	u := Usage{
+		Time:            cur.Time,
+		MemoryUsage:     cur.MemoryUsage,
+		NetworkRxBytes:  cur.NetworkRx,
+		NetworkTxBytes:  cur.NetworkTx,
+		BlockReadBytes:  cur.BlockRead,
+		BlockWriteBytes: cur.BlockWrite,
+	}
+
+	cpuDelta := float64(cur.CPUTotal) - float64(prev.CPUTotal)
+	systemDelta := float64(cur.SystemCPU) - float64(prev.SystemCPU)
+	if cpuDelta > 0 && systemDelta > 0 {
+		u.CPUPercent = cpuDelta / systemDelta * float64(cur.OnlineCPUs) * 100
+	}
+	if cur.MemoryLimit > 0 {
+		u.MemoryPercent = float64(cur.MemoryUsage) / float64(cur.MemoryLimit) * 100
+	}
+	return u
+}
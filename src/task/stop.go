@@ -0,0 +1,192 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
+	"github.com/utkarsh5026/Orchestra/logging"
+)
+
+const (
+	// DefaultStopSignal is sent to a container when its task does not declare a StopSignal.
+	DefaultStopSignal = "SIGTERM"
+	// DefaultStopGracePeriod is how long a container may take to exit after its stop
+	// signal when its task does not declare a StopGracePeriod.
+	DefaultStopGracePeriod = 10 * time.Second
+)
+
+// StopOutcome records which path was taken to stop a task's container.
+type StopOutcome string
+
+const (
+	// StopGraceful means the container exited on its stop signal within the grace period.
+	StopGraceful StopOutcome = "Graceful"
+	// StopKilled means the grace period expired and the container was sent SIGKILL.
+	StopKilled StopOutcome = "Killed"
+	// StopNotRunning means the container had already exited before it was signalled.
+	StopNotRunning StopOutcome = "NotRunning"
+)
+
+// Hook is an action run against a task's container, either a command executed
+// inside the container or an HTTP request to one of its ports.
+type Hook struct {
+	Exec []string  `json:",omitempty"`
+	HTTP *HTTPHook `json:",omitempty"`
+}
+
+// HTTPHook describes an HTTP request sent to a port of the container.
+type HTTPHook struct {
+	// Port is the container port, e.g. "8080/tcp", published on the worker.
+	Port nat.Port
+	Path string
+	// Method defaults to GET.
+	Method string `json:",omitempty"`
+}
+
+// gracefulStop runs the pre-stop hook, sends the stop signal and waits until the
+// grace period expires, escalating to SIGKILL if the container is still running.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the whole operation
+//   - cid: The ID of the container to stop
+//
+// Returns:
+//   - StopOutcome: The path that was taken to stop the container
+//   - error: If the container could not be signalled or killed
+func (d *Docker) gracefulStop(ctx context.Context, cid string) (StopOutcome, error) {
+	grace := d.Config.StopGracePeriod
+	if grace <= 0 {
+		grace = DefaultStopGracePeriod
+	}
+	graceCtx, cancel := context.WithTimeout(ctx, grace)
+	defer cancel()
+
+	if d.Config.PreStop != nil {
+		if err := d.runHook(graceCtx, cid, *d.Config.PreStop); err != nil {
+			d.logger().Warn("Pre-stop hook failed", logging.ContainerID, cid, "error", err)
+		}
+	}
+
+	cli := d.Client.API()
+	statusCh, errCh := cli.ContainerWait(graceCtx, cid, container.WaitConditionNotRunning)
+
+	signal := d.Config.StopSignal
+	if signal == "" {
+		signal = DefaultStopSignal
+	}
+	if err := cli.ContainerKill(graceCtx, cid, signal); err != nil {
+		if errdefs.IsConflict(err) {
+			return StopNotRunning, nil
+		}
+		if graceCtx.Err() == nil {
+			return "", fmt.Errorf("failed to send %s to container %s: %w", signal, cid, err)
+		}
+	}
+
+	select {
+	case <-statusCh:
+		return StopGraceful, nil
+	case err := <-errCh:
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if graceCtx.Err() == nil {
+			return "", fmt.Errorf("failed waiting for container %s to exit: %w", cid, err)
+		}
+	}
+
+	d.logger().Warn("Container did not exit within its grace period, sending SIGKILL", logging.ContainerID, cid, "grace_period", grace)
+	killCtx, cancelKill := withTimeout(ctx, d.Client.Timeouts.Stop)
+	defer cancelKill()
+	if err := cli.ContainerKill(killCtx, cid, "SIGKILL"); err != nil && !errdefs.IsConflict(err) {
+		return "", fmt.Errorf("failed to kill container %s: %w", cid, err)
+	}
+	return StopKilled, nil
+}
+
+// runHook executes h against the container cid.
+func (d *Docker) runHook(ctx context.Context, cid string, h Hook) error {
+	switch {
+	case len(h.Exec) > 0:
+		return d.execHook(ctx, cid, h.Exec)
+	case h.HTTP != nil:
+		return d.httpHook(ctx, cid, *h.HTTP)
+	}
+	return nil
+}
+
+func (d *Docker) execHook(ctx context.Context, cid string, cmd []string) error {
+	cli := d.Client.API()
+	exec, err := cli.ContainerExecCreate(ctx, cid, container.ExecOptions{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create exec: %w", err)
+	}
+
+	resp, err := cli.ContainerExecAttach(ctx, exec.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to attach to exec: %w", err)
+	}
+	defer resp.Close()
+
+	if _, err := stdcopy.StdCopy(os.Stdout, os.Stderr, resp.Reader); err != nil {
+		return fmt.Errorf("failed to read exec output: %w", err)
+	}
+
+	inspect, err := cli.ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		return fmt.Errorf("failed to inspect exec: %w", err)
+	}
+	if inspect.ExitCode != 0 {
+		return fmt.Errorf("command %v exited with code %d", cmd, inspect.ExitCode)
+	}
+	return nil
+}
+
+func (d *Docker) httpHook(ctx context.Context, cid string, h HTTPHook) error {
+	inspect, err := d.Client.API().ContainerInspect(ctx, cid)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	bindings := inspect.NetworkSettings.Ports[h.Port]
+	if len(bindings) == 0 {
+		return fmt.Errorf("port %s is not published", h.Port)
+	}
+	host := bindings[0].HostIP
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+
+	method := h.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	url := fmt.Sprintf("http://%s%s", net.JoinHostPort(host, bindings[0].HostPort), h.Path)
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned %s", method, url, resp.Status)
+	}
+	return nil
+}
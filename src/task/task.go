@@ -20,22 +20,75 @@ type Task struct {
 	PortBindings  map[string]string
 	StartTime     time.Time
 	EndTime       time.Time
+	// PullPolicy controls when the worker pulls Image; empty means PullAlways.
+	PullPolicy PullPolicy `json:",omitempty"`
+	// ImagePullSecret names a manager-held registry secret used to pull Image.
+	ImagePullSecret string `json:",omitempty"`
+	// StopSignal is sent to the container when the task is stopped; empty means SIGTERM.
+	StopSignal string `json:",omitempty"`
+	// StopGracePeriod is how long the container may take to exit before it is
+	// killed; zero means DefaultStopGracePeriod.
+	StopGracePeriod time.Duration `json:",omitempty"`
+	// PreStop is run against the container before the stop signal is sent.
+	PreStop *Hook `json:",omitempty"`
+	// StopOutcome records how the container was stopped.
+	StopOutcome StopOutcome `json:",omitempty"`
+	// Kind is KindService or KindJob; empty means KindService.
+	Kind Kind `json:",omitempty"`
+	// ActiveDeadline bounds how long each attempt of a job may run before it is killed.
+	ActiveDeadline time.Duration `json:",omitempty"`
+	// BackoffLimit is the number of times a failed job is retried.
+	BackoffLimit int `json:",omitempty"`
+	// Attempts counts how many times a failed job has been retried so far.
+	Attempts int `json:",omitempty"`
+	// TTLAfterFinished is how long a finished job is kept before it is cleaned up;
+	// zero keeps it forever.
+	TTLAfterFinished time.Duration `json:",omitempty"`
+	ExitCode         int           `json:",omitempty"`
+	// Reason explains why a task failed when its exit code does not.
+	Reason string   `json:",omitempty"`
+	Env    []string `json:",omitempty"`
+	// Artifacts are copied out of the container when a job completes.
+	Artifacts []Artifact `json:",omitempty"`
+	// OutputsFile is the path of a KEY=VALUE file in the container that is read
+	// into Outputs when a job completes.
+	OutputsFile string            `json:",omitempty"`
+	Outputs     map[string]string `json:",omitempty"`
+	// Inputs are artifacts of upstream tasks mounted into the container.
+	Inputs []ArtifactInput `json:",omitempty"`
+	// HealthCheck is run periodically against the running container by the worker.
+	HealthCheck *Hook  `json:",omitempty"`
+	Health      Health `json:",omitempty"`
+	// Usage is the latest resource utilization of the running container.
+	Usage *Usage `json:",omitempty"`
+	// Labels are key/value pairs used to select tasks, e.g. when listing them.
+	Labels map[string]string `json:",omitempty"`
 }
 
 type Config struct {
-	Name          string
-	AttachStdin   bool
-	AttachStdout  bool
-	AttachStderr  bool
-	ExposedPorts  nat.PortSet
-	Cmd           []string
-	Image         string
-	Cpu           float64
-	Memory        int64
-	Disk          int64
-	Env           []string
-	RestartPolicy string
-	Runtime       Runtime
+	// TaskID identifies the task in log records.
+	TaskID          uuid.UUID
+	Name            string
+	AttachStdin     bool
+	AttachStdout    bool
+	AttachStderr    bool
+	ExposedPorts    nat.PortSet
+	Cmd             []string
+	Image           string
+	Cpu             float64
+	Memory          int64
+	Disk            int64
+	Env             []string
+	RestartPolicy   string
+	PullPolicy      PullPolicy
+	StopSignal      string
+	StopGracePeriod time.Duration
+	PreStop         *Hook
+	// Binds are host paths mounted into the container, in "host:container[:ro]" form.
+	Binds []string
+	// RegistryAuth is the base64 encoded registry credentials used when pulling Image.
+	RegistryAuth string
+	Runtime      Runtime
 }
 
 type Runtime struct {
@@ -44,12 +97,18 @@ type Runtime struct {
 
 func NewConfig(t *Task) *Config {
 	return &Config{
-		Name:          t.Name,
-		ExposedPorts:  t.ExposedPorts,
-		Image:         t.Image,
-		Cpu:           t.Cpu,
-		Memory:        t.Memory,
-		Disk:          t.Disk,
-		RestartPolicy: t.RestartPolicy,
+		TaskID:          t.ID,
+		Name:            t.Name,
+		ExposedPorts:    t.ExposedPorts,
+		Image:           t.Image,
+		Cpu:             t.Cpu,
+		Memory:          t.Memory,
+		Disk:            t.Disk,
+		RestartPolicy:   t.RestartPolicy,
+		PullPolicy:      t.PullPolicy,
+		StopSignal:      t.StopSignal,
+		StopGracePeriod: t.StopGracePeriod,
+		PreStop:         t.PreStop,
+		Env:             t.Env,
 	}
 }
@@ -0,0 +1,244 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/utkarsh5026/Orchestra/handler"
+)
+
+// Resource bounds of a task.
+const (
+	// MaxCpu is the largest number of CPUs a task may request.
+	MaxCpu = 1024
+	// MinMemory is the smallest memory limit Docker accepts; zero means no limit.
+	MinMemory = 6 * 1024 * 1024
+)
+
+// RestartPolicies lists the restart policies a task may declare; empty means "no".
+var RestartPolicies = []string{"", "no", "always", "unless-stopped", "on-failure"}
+
+var (
+	namePattern   = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+	signalPattern = regexp.MustCompile(`^(SIG[A-Z0-9+-]+|[0-9]+)$`)
+)
+
+// Validate checks the spec of a task submitted to the manager, so that a task
+// that no worker could run is rejected up front instead of failing on a worker.
+// The status fields set by the manager and workers are not checked, except that
+// a new task must be Pending.
+//
+// Returns:
+//   - []handler.FieldError: One entry per invalid field, named after the fields
+//     of Task, e.g. "ExposedPorts[80/tcp]"; empty if the task is valid
+func (t *Task) Validate() []handler.FieldError {
+	var errs []handler.FieldError
+	add := func(field, format string, args ...any) {
+		errs = append(errs, handler.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if t.State != Pending {
+		add("State", "must be Pending for a new task, got %s", t.State)
+	}
+	if t.Name != "" {
+		if len(t.Name) > 128 {
+			add("Name", "must be at most 128 characters")
+		} else if !namePattern.MatchString(t.Name) {
+			add("Name", "must start with a letter or digit and contain only letters, digits, '_', '.' and '-'")
+		}
+	}
+	if strings.TrimSpace(t.Image) == "" {
+		add("Image", "is required")
+	} else if strings.ContainsAny(t.Image, " \t\n") {
+		add("Image", "must not contain whitespace")
+	}
+
+	if t.Cpu < 0 || t.Cpu > MaxCpu {
+		add("Cpu", "must be between 0 and %d", MaxCpu)
+	}
+	if t.Memory < 0 {
+		add("Memory", "must not be negative")
+	} else if t.Memory > 0 && t.Memory < MinMemory {
+		add("Memory", "must be 0 (no limit) or at least %d bytes", MinMemory)
+	}
+	if t.Disk < 0 {
+		add("Disk", "must not be negative")
+	}
+
+	if !slices.Contains(RestartPolicies, t.RestartPolicy) {
+		add("RestartPolicy", "must be one of %s", strings.Join(RestartPolicies[1:], ", "))
+	}
+	if !t.PullPolicy.IsValid() {
+		add("PullPolicy", "must be one of %s, %s, %s", PullAlways, PullIfNotPresent, PullNever)
+	}
+	switch t.Kind {
+	case "", KindService, KindJob:
+	default:
+		add("Kind", "must be %s or %s", KindService, KindJob)
+	}
+
+	for _, p := range slices.Sorted(maps.Keys(t.ExposedPorts)) {
+		if err := validatePort(p); err != nil {
+			add(fmt.Sprintf("ExposedPorts[%s]", p), "%v", err)
+		}
+	}
+	for _, p := range slices.Sorted(maps.Keys(t.PortBindings)) {
+		host := t.PortBindings[p]
+		field := fmt.Sprintf("PortBindings[%s]", p)
+		if err := validatePort(nat.Port(p)); err != nil {
+			add(field, "%v", err)
+		}
+		if !validPortNumber(host) {
+			add(field, "host port %q must be a number between 1 and 65535", host)
+		}
+	}
+	for i, e := range t.Env {
+		if k, _, ok := strings.Cut(e, "="); !ok || k == "" {
+			add(fmt.Sprintf("Env[%d]", i), "must be in KEY=VALUE form")
+		}
+	}
+	if _, ok := t.Labels[""]; ok {
+		add("Labels", "keys must not be empty")
+	}
+
+	if t.StopSignal != "" && !signalPattern.MatchString(t.StopSignal) {
+		add("StopSignal", "must be a signal name such as SIGTERM or a signal number")
+	}
+	if t.StopGracePeriod < 0 {
+		add("StopGracePeriod", "must not be negative")
+	}
+	validateHook(t.PreStop, "PreStop", add)
+	validateHook(t.HealthCheck, "HealthCheck", add)
+
+	if t.ActiveDeadline < 0 {
+		add("ActiveDeadline", "must not be negative")
+	}
+	if t.BackoffLimit < 0 {
+		add("BackoffLimit", "must not be negative")
+	}
+	if t.TTLAfterFinished < 0 {
+		add("TTLAfterFinished", "must not be negative")
+	}
+	for i, a := range t.Artifacts {
+		field := fmt.Sprintf("Artifacts[%d]", i)
+		if err := ValidateArtifactName(a.Name); err != nil {
+			add(field+".Name", "%v", err)
+		}
+		if !path.IsAbs(a.Path) {
+			add(field+".Path", "must be an absolute path")
+		}
+	}
+	for i, in := range t.Inputs {
+		field := fmt.Sprintf("Inputs[%d]", i)
+		if err := ValidateArtifactName(in.Name); err != nil {
+			add(field+".Name", "%v", err)
+		}
+		if err := ValidateMountPath(in.MountPath); err != nil {
+			add(field+".MountPath", "%v", err)
+		}
+	}
+	if t.OutputsFile != "" && !path.IsAbs(t.OutputsFile) {
+		add("OutputsFile", "must be an absolute path")
+	}
+	return errs
+}
+
+// ValidationError is returned for an invalid task template, listing its
+// invalid fields.
+type ValidationError struct {
+	Fields []handler.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	return "invalid task: " + strings.Join(parts, "; ")
+}
+
+// ValidateTemplate checks, with Validate, a template from which the manager
+// creates tasks, such as the template of a service or the task of a workflow
+// step.
+//
+// Parameters:
+//   - prefix: Prepended to the names of the invalid fields, e.g. "Template."
+//
+// Returns:
+//   - []handler.FieldError: One entry per invalid field; empty if the template is valid
+func (t *Task) ValidateTemplate(prefix string) []handler.FieldError {
+	errs := t.Validate()
+	for i := range errs {
+		errs[i].Field = prefix + errs[i].Field
+	}
+	return errs
+}
+
+// ValidateArtifactName checks that name can be used as the name of the file
+// an artifact is stored in: it must not be empty, ".", ".." or contain a path
+// separator.
+func ValidateArtifactName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("is required")
+	case name == "." || name == "..":
+		return fmt.Errorf("must not be %q", name)
+	case strings.ContainsAny(name, `/\`):
+		return errors.New("must not contain a path separator")
+	}
+	return nil
+}
+
+// ValidateMountPath checks that p is an absolute path under InputsDir, at which
+// an input artifact may be mounted.
+func ValidateMountPath(p string) error {
+	if !path.IsAbs(p) || path.Clean(p) != p || !strings.HasPrefix(p, InputsDir+"/") {
+		return fmt.Errorf("must be a clean absolute path under %s", InputsDir)
+	}
+	return nil
+}
+
+// validatePort checks that p is a container port such as "80/tcp".
+func validatePort(p nat.Port) error {
+	if !validPortNumber(p.Port()) {
+		return fmt.Errorf("port %q must be a number between 1 and 65535", p.Port())
+	}
+	switch p.Proto() {
+	case "tcp", "udp", "sctp":
+		return nil
+	}
+	return fmt.Errorf("protocol %q must be tcp, udp or sctp", p.Proto())
+}
+
+// validPortNumber reports whether s is a port number between 1 and 65535.
+func validPortNumber(s string) bool {
+	n, err := strconv.ParseUint(s, 10, 16)
+	return err == nil && n > 0
+}
+
+// validateHook checks that a hook declares exactly one of Exec and HTTP.
+func validateHook(h *Hook, field string, add func(field, format string, args ...any)) {
+	if h == nil {
+		return
+	}
+	switch {
+	case len(h.Exec) == 0 && h.HTTP == nil:
+		add(field, "must declare Exec or HTTP")
+	case len(h.Exec) > 0 && h.HTTP != nil:
+		add(field, "must declare only one of Exec and HTTP")
+	case h.HTTP != nil:
+		if err := validatePort(h.HTTP.Port); err != nil {
+			add(field+".HTTP.Port", "%v", err)
+		}
+		if h.HTTP.Path != "" && !strings.HasPrefix(h.HTTP.Path, "/") {
+			add(field+".HTTP.Path", "must start with /")
+		}
+	}
+}
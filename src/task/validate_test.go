@@ -0,0 +1,150 @@
+package task
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+)
+
+func TestValidate(t *testing.T) {
+	valid := func() Task {
+		return Task{Name: "web", Image: "nginx:1.27", State: Pending}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(t *Task)
+		// fields lists the invalid fields reported, in order.
+		fields []string
+	}{
+		{name: "valid", modify: func(t *Task) {}},
+		{
+			name: "valid with every field set",
+			modify: func(t *Task) {
+				t.Cpu = 2
+				t.Memory = MinMemory
+				t.RestartPolicy = "on-failure"
+				t.PullPolicy = PullIfNotPresent
+				t.Kind = KindJob
+				t.ExposedPorts = nat.PortSet{"80/tcp": {}}
+				t.PortBindings = map[string]string{"80/tcp": "8080"}
+				t.Env = []string{"A=1", "B="}
+				t.StopSignal = "SIGINT"
+				t.PreStop = &Hook{Exec: []string{"sleep", "1"}}
+				t.HealthCheck = &Hook{HTTP: &HTTPHook{Port: "80/tcp", Path: "/healthz"}}
+				t.Artifacts = []Artifact{{Name: "report", Path: "/out/report.txt"}}
+				t.Inputs = []ArtifactInput{{Name: "data", MountPath: InputsDir + "/data"}}
+				t.OutputsFile = "/out/outputs.json"
+			},
+		},
+		{name: "not pending", modify: func(t *Task) { t.State = Running }, fields: []string{"State"}},
+		{name: "bad name", modify: func(t *Task) { t.Name = "-web" }, fields: []string{"Name"}},
+		{name: "long name", modify: func(t *Task) { t.Name = strings.Repeat("a", 129) }, fields: []string{"Name"}},
+		{name: "missing image", modify: func(t *Task) { t.Image = " " }, fields: []string{"Image"}},
+		{name: "image with whitespace", modify: func(t *Task) { t.Image = "nginx 1.27" }, fields: []string{"Image"}},
+		{
+			name: "resources out of range",
+			modify: func(t *Task) {
+				t.Cpu = MaxCpu + 1
+				t.Memory = 1024
+				t.Disk = -1
+			},
+			fields: []string{"Cpu", "Memory", "Disk"},
+		},
+		{
+			name: "unknown policies and kind",
+			modify: func(t *Task) {
+				t.RestartPolicy = "sometimes"
+				t.PullPolicy = "Later"
+				t.Kind = "Daemon"
+			},
+			fields: []string{"RestartPolicy", "PullPolicy", "Kind"},
+		},
+		{
+			name: "bad ports",
+			modify: func(t *Task) {
+				t.ExposedPorts = nat.PortSet{"0/tcp": {}, "80/icmp": {}}
+				t.PortBindings = map[string]string{"80/tcp": "70000"}
+			},
+			fields: []string{"ExposedPorts[0/tcp]", "ExposedPorts[80/icmp]", "PortBindings[80/tcp]"},
+		},
+		{
+			name: "bad env and labels",
+			modify: func(t *Task) {
+				t.Env = []string{"A=1", "B", "=2"}
+				t.Labels = map[string]string{"": "x"}
+			},
+			fields: []string{"Env[1]", "Env[2]", "Labels"},
+		},
+		{
+			name: "bad stop settings",
+			modify: func(t *Task) {
+				t.StopSignal = "TERM"
+				t.StopGracePeriod = -time.Second
+				t.PreStop = &Hook{}
+			},
+			fields: []string{"StopSignal", "StopGracePeriod", "PreStop"},
+		},
+		{
+			name: "bad health check",
+			modify: func(t *Task) {
+				t.HealthCheck = &Hook{HTTP: &HTTPHook{Port: "80/tcp", Path: "healthz"}}
+			},
+			fields: []string{"HealthCheck.HTTP.Path"},
+		},
+		{
+			name: "health check with exec and http",
+			modify: func(t *Task) {
+				t.HealthCheck = &Hook{Exec: []string{"true"}, HTTP: &HTTPHook{Port: "80/tcp"}}
+			},
+			fields: []string{"HealthCheck"},
+		},
+		{
+			name: "negative job limits",
+			modify: func(t *Task) {
+				t.ActiveDeadline = -time.Second
+				t.BackoffLimit = -1
+				t.TTLAfterFinished = -time.Second
+			},
+			fields: []string{"ActiveDeadline", "BackoffLimit", "TTLAfterFinished"},
+		},
+		{
+			name: "bad artifacts and inputs",
+			modify: func(t *Task) {
+				t.Artifacts = []Artifact{{Name: "a/b", Path: "out"}}
+				t.Inputs = []ArtifactInput{{Name: "..", MountPath: "/data"}}
+				t.OutputsFile = "outputs.json"
+			},
+			fields: []string{"Artifacts[0].Name", "Artifacts[0].Path", "Inputs[0].Name", "Inputs[0].MountPath", "OutputsFile"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := valid()
+			tt.modify(&task)
+
+			var got []string
+			for _, f := range task.Validate() {
+				got = append(got, f.Field)
+			}
+			if !slices.Equal(got, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestValidateTemplatePrefixesFields(t *testing.T) {
+	tmpl := Task{State: Pending, Cpu: -1}
+	var got []string
+	for _, f := range tmpl.ValidateTemplate("Template.") {
+		got = append(got, f.Field)
+	}
+	if want := []string{"Template.Image", "Template.Cpu"}; !slices.Equal(got, want) {
+		t.Errorf("ValidateTemplate() fields = %v, want %v", got, want)
+	}
+}
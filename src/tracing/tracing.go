@@ -0,0 +1,128 @@
+// Package tracing configures the OpenTelemetry tracer provider shared by the
+// manager, the worker and their Docker operations.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Exporter selects where spans are sent.
+type Exporter string
+
+const (
+	// ExporterNone disables tracing. This is the default.
+	ExporterNone Exporter = "none"
+	// ExporterStdout writes spans as JSON to standard output.
+	ExporterStdout Exporter = "stdout"
+	// ExporterOTLP sends spans over OTLP/HTTP to the endpoint configured by the
+	// standard OTEL_EXPORTER_OTLP_ENDPOINT environment variables.
+	ExporterOTLP Exporter = "otlp"
+)
+
+// Setup installs a global tracer provider exporting to exp and the W3C trace
+// context propagator used to carry traces between the manager and workers.
+//
+// Parameters:
+//   - ctx: Context used to create the exporter
+//   - serviceName: The service.name resource attribute, e.g. "orchestra-manager"
+//   - exp: The exporter to use; an empty exporter disables tracing
+//
+// Returns:
+//   - func(context.Context) error: Flushes pending spans and shuts the provider down
+//   - error: If the exporter is unknown or cannot be created
+func Setup(ctx context.Context, serviceName string, exp Exporter) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch exp {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", exp)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", exp, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Inject returns the trace context of ctx in a form that can be stored with a
+// queued item and restored later with Extract.
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract returns ctx carrying the trace context stored by Inject, so that spans
+// started from it continue the original trace.
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// RecordError marks span as failed with err. It does nothing if err is nil.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Middleware returns HTTP middleware that starts a server span for every request
+// except metrics scrapes, continuing the trace propagated by the caller.
+//
+// Parameters:
+//   - operation: The name of the server, used as a prefix of span names
+func Middleware(operation string) func(http.Handler) http.Handler {
+	return otelhttp.NewMiddleware(operation,
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			return r.URL.Path != "/metrics"
+		}),
+		otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+			return fmt.Sprintf("%s %s %s", operation, r.Method, r.URL.Path)
+		}),
+	)
+}
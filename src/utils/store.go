@@ -1,7 +1,7 @@
 package utils
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/utkarsh5026/Orchestra/store"
 )
@@ -14,6 +14,6 @@ import (
 //   - data: The value to store
 func UpdateStore[K comparable, V any](store store.Store[K, V], key K, data V) {
 	if err := store.Put(key, data); err != nil {
-		log.Printf("Error updating store: for key %v: %v\n", key, err)
+		slog.Error("Error updating store", "key", key, "error", err)
 	}
 }
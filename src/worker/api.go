@@ -1,12 +1,18 @@
 package worker
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"sync"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/utkarsh5026/Orchestra/logging"
+	"github.com/utkarsh5026/Orchestra/metrics"
+	"github.com/utkarsh5026/Orchestra/tracing"
 )
 
 type Api struct {
@@ -14,23 +20,63 @@ type Api struct {
 	Port    int
 	Worker  *Worker
 	Router  *chi.Mux
+
+	mu     sync.Mutex
+	server *http.Server
 }
 
 func (a *Api) initializeRouter() {
 	a.Router = chi.NewRouter()
-	a.Router.Use(middleware.Logger)
+	a.Router.Use(logging.Middleware)
 	a.Router.Use(middleware.Recoverer)
+	a.Router.Use(tracing.Middleware("worker"))
+
+	a.Router.Handle("/metrics", metrics.Handler())
 
 	a.Router.Route("/tasks", func(r chi.Router) {
 		r.Post("/", a.StartTaskHandler)
 		r.Get("/", a.GetTasksHandler)
+		r.Get("/stats", a.GetStatsHandler)
+		r.Get("/{taskID}/stats", a.GetTaskStatsHandler)
+		r.Get("/{taskID}/logs", a.GetTaskLogsHandler)
 		r.Delete("/{taskID}", a.StopTaskHandler)
+		r.Delete("/{taskID}/record", a.RemoveTaskHandler)
+		r.Get("/{taskID}/artifacts/{name}", a.GetArtifactHandler)
 	})
 }
 
-func (a *Api) Start() {
+// Start serves the API on Address:Port until Shutdown is called.
+//
+// Returns:
+//   - error: If the server cannot listen or fails; nil after Shutdown
+func (a *Api) Start() error {
 	a.initializeRouter()
 
-	log.Printf("Starting server on %s:%d", a.Address, a.Port)
-	http.ListenAndServe(fmt.Sprintf("%s:%d", a.Address, a.Port), a.Router)
+	if err := metrics.RegisterNode(a.Worker.Name); err != nil {
+		slog.Error("Error registering node metrics", "error", err)
+	}
+
+	a.mu.Lock()
+	a.server = &http.Server{Addr: fmt.Sprintf("%s:%d", a.Address, a.Port), Handler: a.Router}
+	srv := a.server
+	a.mu.Unlock()
+
+	slog.Info("Starting worker API server", logging.Worker, a.Worker.Name, "address", a.Address, "port", a.Port)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("worker API server failed: %w", err)
+	}
+	return nil
+}
+
+// Shutdown stops the server from accepting new requests and waits for the
+// in-flight ones to finish until ctx expires.
+func (a *Api) Shutdown(ctx context.Context) error {
+	a.mu.Lock()
+	srv := a.server
+	a.mu.Unlock()
+
+	if srv == nil {
+		return nil
+	}
+	return srv.Shutdown(ctx)
 }
@@ -0,0 +1,172 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// ArtifactPath returns the path of the tar archive holding the artifact name
+// collected from task id.
+//
+// Returns:
+//   - string: The path, which is always inside the artifact directory of the task
+//   - error: If name is not a valid artifact name
+func (w *Worker) ArtifactPath(id uuid.UUID, name string) (string, error) {
+	if err := task.ValidateArtifactName(name); err != nil {
+		return "", fmt.Errorf("invalid artifact name %q: %w", name, err)
+	}
+	dir := w.artifactDir(id)
+	p := filepath.Join(dir, name+".tar")
+	if rel, err := filepath.Rel(dir, p); err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("invalid artifact name %q: escapes the artifact directory", name)
+	}
+	return p, nil
+}
+
+func (w *Worker) artifactDir(id uuid.UUID) string {
+	return filepath.Join(w.DataDir, "artifacts", id.String())
+}
+
+func (w *Worker) inputDir(id uuid.UUID) string {
+	return filepath.Join(w.DataDir, "inputs", id.String())
+}
+
+// collectOutputs copies the declared artifacts out of the exited container of a
+// completed job and reads its outputs file into t.Outputs.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the Docker requests
+//   - t: The completed job
+//
+// Returns:
+//   - error: If an artifact or the outputs file cannot be collected
+func (w *Worker) collectOutputs(ctx context.Context, t *task.Task) error {
+	d := task.NewDocker(*task.NewConfig(t), w.Docker)
+
+	for _, a := range t.Artifacts {
+		p, err := w.ArtifactPath(t.ID, a.Name)
+		if err != nil {
+			return err
+		}
+		rc, err := d.CopyFrom(ctx, t.ContainerID, a.Path)
+		if err != nil {
+			return err
+		}
+		err = saveArtifact(p, rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("failed to save artifact %s: %w", a.Name, err)
+		}
+	}
+
+	if t.OutputsFile == "" {
+		return nil
+	}
+
+	rc, err := d.CopyFrom(ctx, t.ContainerID, t.OutputsFile)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	outputs, err := task.ReadOutputs(rc)
+	if err != nil {
+		return err
+	}
+	t.Outputs = outputs
+	return nil
+}
+
+// stageInputs fetches the input artifacts of t, from this worker or the worker
+// holding them, and extracts each one into its own directory.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the downloads
+//   - t: The task about to be started
+//
+// Returns:
+//   - []string: Read-only binds mounting each input at its MountPath
+//   - error: If an input cannot be fetched or extracted
+func (w *Worker) stageInputs(ctx context.Context, t *task.Task) ([]string, error) {
+	var binds []string
+	for _, in := range t.Inputs {
+		if err := task.ValidateMountPath(in.MountPath); err != nil {
+			return nil, fmt.Errorf("invalid mount path %q of input %s: %w", in.MountPath, in.Name, err)
+		}
+		rc, err := w.openArtifact(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+
+		dir := filepath.Join(w.inputDir(t.ID), in.TaskID.String(), in.Name)
+		err = task.ExtractTar(rc, dir)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract input %s: %w", in.Name, err)
+		}
+		binds = append(binds, fmt.Sprintf("%s:%s:ro", dir, in.MountPath))
+	}
+	return binds, nil
+}
+
+// openArtifact opens an input artifact held by this worker, or fetches it from
+// the worker holding it if that worker is one of Peers.
+func (w *Worker) openArtifact(ctx context.Context, in task.ArtifactInput) (io.ReadCloser, error) {
+	p, err := w.ArtifactPath(in.TaskID, in.Name)
+	if err != nil {
+		return nil, err
+	}
+	if f, err := os.Open(p); err == nil {
+		return f, nil
+	}
+	if !slices.Contains(w.Peers, in.Worker) {
+		return nil, fmt.Errorf("failed to fetch artifact %s: worker %q is not a peer", in.Name, in.Worker)
+	}
+
+	url := fmt.Sprintf("http://%s/tasks/%s/artifacts/%s", in.Worker, in.TaskID, in.Name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for artifact %s: %w", in.Name, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch artifact %s from worker %s: %w", in.Name, in.Worker, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch artifact %s from worker %s: %s", in.Name, in.Worker, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// removeTaskData deletes the artifacts and staged inputs of task id.
+func (w *Worker) removeTaskData(id uuid.UUID) error {
+	if err := os.RemoveAll(w.artifactDir(id)); err != nil {
+		return err
+	}
+	return os.RemoveAll(w.inputDir(id))
+}
+
+func saveArtifact(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
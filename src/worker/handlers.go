@@ -2,17 +2,26 @@ package worker
 
 import (
 	"encoding/json"
+	"errors"
 	"github.com/utkarsh5026/Orchestra/handler"
-	"log"
+	"io"
 	"net/http"
+	"os"
+	"strconv"
 
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/logging"
+	"github.com/utkarsh5026/Orchestra/store"
 	"github.com/utkarsh5026/Orchestra/task"
+	"github.com/utkarsh5026/Orchestra/tracing"
 )
 
 // StartTaskHandler handles HTTP POST requests to start a new task
 // It decodes the task event from the request body and adds the task to the worker's queue
+// Registry credentials sent along with the event are kept for pulling the task's image
+// The trace propagated by the manager is kept so that starting the task continues it
 //
 // Parameters:
 //   - w: HTTP response writer to send the response
@@ -37,38 +46,54 @@ func (a *Api) StartTaskHandler(
 		return
 	}
 
+	if taskEvent.RegistryAuth != nil {
+		a.Worker.SetPullAuth(taskEvent.Task.ID, *taskEvent.RegistryAuth)
+	}
+
+	a.Worker.SetTraceContext(taskEvent.Task.ID, tracing.Inject(r.Context()))
 	a.Worker.AddTask(&taskEvent.Task)
-	log.Printf("Task added to the queue: %s", taskEvent.Task.ID)
+	a.Worker.taskLogger(taskEvent.Task.ID).Info("Task added to the queue", logging.EventID, taskEvent.ID, "state", taskEvent.State)
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(taskEvent.Task)
 }
 
-// GetTasksHandler handles HTTP GET requests to retrieve all tasks from the worker
-// It returns a JSON array of all tasks currently tracked by the worker
+// GetTasksHandler handles HTTP GET requests to retrieve tasks from the worker
+// It returns a JSON array of one page of the tasks tracked by the worker, filtered,
+// sorted and paged by the query parameters read by task.ParseQuery
+// The cursor of the next page is sent in the X-Next-Cursor header
 //
 // Parameters:
 //   - w: HTTP response writer to send the response
-//   - r: HTTP request (unused)
+//   - r: HTTP request carrying the query parameters
 //
 // Returns HTTP 200 with JSON array of tasks on success
-// Returns HTTP 500 if there is an error encoding the response
+// Returns HTTP 400 if a query parameter or the cursor is invalid
+// Returns HTTP 500 if the tasks cannot be read
 func (a *Api) GetTasksHandler(
 	w http.ResponseWriter,
 	r *http.Request,
 ) {
+	q, err := task.ParseQuery(r.URL.Query())
+	if err != nil {
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid task query", err))
+		return
+	}
+
 	ts, err := a.Worker.GetTasks()
 	if err != nil {
 		resErr := handler.Err(http.StatusInternalServerError, "Error getting tasks", err)
 		handler.SendErr(w, resErr)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	err = json.NewEncoder(w).Encode(ts)
+	page, next, total, err := q.Page(ts)
 	if err != nil {
-		log.Printf("Error encoding tasks: %v", err)
-		resErr := handler.Err(http.StatusInternalServerError, "Error encoding tasks", err)
-		handler.SendErr(w, resErr)
+		handler.SendErr(w, handler.Err(http.StatusBadRequest, "Invalid task query", err))
+		return
+	}
+
+	if err := handler.SendPage(w, page, next, total); err != nil {
+		a.Worker.logger().Error("Error encoding tasks", "error", err)
 	}
 }
 
@@ -80,6 +105,7 @@ func (a *Api) GetTasksHandler(
 //   - r: HTTP request containing the task ID in the URL path
 //
 // The handler expects a valid UUID as the taskID URL parameter
+// If the task is still starting (e.g. pulling its image) or waiting in the queue, its start is cancelled instead
 // Returns HTTP 400 if task ID is missing or invalid
 // Returns HTTP 404 if task is not found
 // Returns HTTP 204 on successful queueing of the stop request
@@ -101,13 +127,18 @@ func (a *Api) StopTaskHandler(
 		return
 	}
 
-	t, err := a.Worker.Db.Get(tID)
-	if err != nil {
-		resErr := handler.Err(http.StatusNotFound, "Task not found", err)
-		handler.SendErr(w, resErr)
+	if a.Worker.CancelTask(tID) {
+		a.Worker.taskLogger(tID).Info("Cancelled start of task")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if a.Worker.CancelQueuedTask(tID) {
+		a.Worker.taskLogger(tID).Info("Cancelled queued start of task")
+		w.WriteHeader(http.StatusNoContent)
+		return
 	}
 
-	taskToStop, err := a.Worker.Db.Get(t.ID)
+	taskToStop, err := a.Worker.Db.Get(tID)
 	if err != nil {
 		resErr := handler.Err(http.StatusNotFound, "Task not found", err)
 		handler.SendErr(w, resErr)
@@ -117,6 +148,193 @@ func (a *Api) StopTaskHandler(
 	taskToStop.State = task.Completed
 	a.Worker.AddTask(taskToStop)
 
-	log.Printf("Adding task %v to stop the container %v\n", taskToStop.ID, taskToStop.ContainerID)
+	a.Worker.taskLogger(taskToStop.ID).Info("Adding task to stop its container", logging.ContainerID, taskToStop.ContainerID)
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// RemoveTaskHandler handles HTTP DELETE requests to remove a finished task
+// It removes the task's container, artifacts and inputs and forgets the task
+//
+// Parameters:
+//   - w: HTTP response writer to send the response
+//   - r: HTTP request containing the task ID in the URL path
+//
+// Returns HTTP 204 once the task is removed
+// Returns HTTP 400 if the task ID is invalid
+// Returns HTTP 404 if the task is not found
+// Returns HTTP 409 if the task is still running
+// Returns HTTP 500 if the container or data cannot be removed
+func (a *Api) RemoveTaskHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	tID, err := uuid.Parse(chi.URLParam(r, "taskID"))
+	if err != nil {
+		resErr := handler.Err(http.StatusBadRequest, "Invalid task ID", err)
+		handler.SendErr(w, resErr)
+		return
+	}
+
+	err = a.Worker.RemoveTask(r.Context(), tID)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		handler.SendErr(w, handler.Err(http.StatusNotFound, "Task not found", err))
+	case errors.Is(err, ErrTaskNotFinished):
+		handler.SendErr(w, handler.Err(http.StatusConflict, "Task is still running", err))
+	case err != nil:
+		handler.SendErr(w, handler.Err(http.StatusInternalServerError, "Error removing task", err))
+	default:
+		a.Worker.taskLogger(tID).Info("Removed task")
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetArtifactHandler handles HTTP GET requests for an artifact collected from a job
+// It streams the tar archive of the artifact so that other workers can stage it as an input
+//
+// Parameters:
+//   - w: HTTP response writer to send the response
+//   - r: HTTP request containing the task ID and artifact name in the URL path
+//
+// Returns HTTP 400 if the task ID or artifact name is invalid
+// Returns HTTP 404 if the artifact has not been collected
+// Returns HTTP 200 with the tar archive on success
+func (a *Api) GetArtifactHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	tID, err := uuid.Parse(chi.URLParam(r, "taskID"))
+	if err != nil {
+		resErr := handler.Err(http.StatusBadRequest, "Invalid task ID", err)
+		handler.SendErr(w, resErr)
+		return
+	}
+
+	p, err := a.Worker.ArtifactPath(tID, chi.URLParam(r, "name"))
+	if err != nil {
+		resErr := handler.Err(http.StatusBadRequest, "Invalid artifact name", err)
+		handler.SendErr(w, resErr)
+		return
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		resErr := handler.Err(http.StatusNotFound, "Artifact not found", err)
+		handler.SendErr(w, resErr)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, f); err != nil {
+		a.Worker.taskLogger(tID).Error("Error sending artifact", "artifact", chi.URLParam(r, "name"), "error", err)
+	}
+}
+
+// GetStatsHandler handles HTTP GET requests for the usage history of every task
+// the worker has sampled
+//
+// Parameters:
+//   - w: HTTP response writer to send the response
+//   - r: HTTP request (unused)
+//
+// Returns HTTP 200 with a JSON array of task stats
+func (a *Api) GetStatsHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(a.Worker.GetAllStats()); err != nil {
+		a.Worker.logger().Error("Error encoding stats", "error", err)
+	}
+}
+
+// GetTaskStatsHandler handles HTTP GET requests for the usage history of a task
+// It returns the CPU, memory, network and block IO samples recorded while the task was running
+//
+// Parameters:
+//   - w: HTTP response writer to send the response
+//   - r: HTTP request containing the task ID in the URL path
+//
+// Returns HTTP 400 if the task ID is invalid
+// Returns HTTP 404 if no usage has been recorded for the task
+// Returns HTTP 200 with the task stats on success
+func (a *Api) GetTaskStatsHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	tID, err := uuid.Parse(chi.URLParam(r, "taskID"))
+	if err != nil {
+		resErr := handler.Err(http.StatusBadRequest, "Invalid task ID", err)
+		handler.SendErr(w, resErr)
+		return
+	}
+
+	stats, ok := a.Worker.GetStats(tID)
+	if !ok {
+		resErr := handler.Err(http.StatusNotFound, "No stats recorded for task", nil)
+		handler.SendErr(w, resErr)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		a.Worker.taskLogger(tID).Error("Error encoding stats", "error", err)
+	}
+}
+
+// GetTaskLogsHandler handles HTTP GET requests for the output of a task's container
+// Stdout and stderr are streamed as plain text; with ?follow=true the stream stays
+// open until the container exits or the client disconnects
+//
+// Parameters:
+//   - w: HTTP response writer to send the response
+//   - r: HTTP request containing the task ID in the URL path and optional follow and tail query parameters
+//
+// Returns HTTP 400 if the task ID or follow parameter is invalid
+// Returns HTTP 404 if the task is unknown or has no container
+// Returns HTTP 500 if Docker cannot return the logs
+// Returns HTTP 200 with the log stream on success
+func (a *Api) GetTaskLogsHandler(
+	w http.ResponseWriter,
+	r *http.Request,
+) {
+	tID, err := uuid.Parse(chi.URLParam(r, "taskID"))
+	if err != nil {
+		resErr := handler.Err(http.StatusBadRequest, "Invalid task ID", err)
+		handler.SendErr(w, resErr)
+		return
+	}
+
+	opts := task.LogOptions{Tail: r.URL.Query().Get("tail")}
+	if f := r.URL.Query().Get("follow"); f != "" {
+		if opts.Follow, err = strconv.ParseBool(f); err != nil {
+			resErr := handler.Err(http.StatusBadRequest, "Invalid follow parameter", err)
+			handler.SendErr(w, resErr)
+			return
+		}
+	}
+
+	rc, err := a.Worker.TaskLogs(r.Context(), tID, opts)
+	if errors.Is(err, store.ErrNotFound) || errors.Is(err, ErrNoContainer) {
+		resErr := handler.Err(http.StatusNotFound, "No logs for task", err)
+		handler.SendErr(w, resErr)
+		return
+	}
+	if err != nil {
+		resErr := handler.Err(http.StatusInternalServerError, "Error getting task logs", err)
+		handler.SendErr(w, resErr)
+		return
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	out := handler.FlushWriter(w)
+	if _, err := stdcopy.StdCopy(out, out, rc); err != nil && r.Context().Err() == nil {
+		a.Worker.taskLogger(tID).Error("Error sending task logs", "error", err)
+	}
+}
@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// ErrNoContainer is returned when logs are requested for a task that has no container.
+var ErrNoContainer = errors.New("task has no container")
+
+// TaskLogs returns the multiplexed stdout and stderr stream of the container of a task.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the stream
+//   - id: The ID of the task
+//   - opts: Which part of the output to return
+//
+// Returns:
+//   - io.ReadCloser: The log stream; the caller must close it
+//   - error: Wrapping store.ErrNotFound if the task is unknown, ErrNoContainer if it
+//     has not been started, or an error if Docker cannot return the logs
+func (w *Worker) TaskLogs(ctx context.Context, id uuid.UUID, opts task.LogOptions) (io.ReadCloser, error) {
+	t, err := w.Db.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task %s: %w", id, err)
+	}
+	if t.ContainerID == "" {
+		return nil, ErrNoContainer
+	}
+
+	d := task.NewDocker(*task.NewConfig(t), w.Docker)
+	return d.Logs(ctx, t.ContainerID, opts)
+}
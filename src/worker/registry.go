@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/api/types/registry"
+)
+
+// registryConfig is the file read by LoadRegistries, in the format of the
+// Docker CLI's config.json.
+type registryConfig struct {
+	Auths map[string]registry.AuthConfig `json:"auths"`
+}
+
+// LoadRegistries reads the worker-side registry credentials from a file in the
+// format of the Docker CLI's config.json:
+//
+//	{"auths": {"ghcr.io": {"auth": "<base64 of user:password>"}}}
+//
+// Each entry may instead set username and password, or identitytoken. Keys may
+// be registry hosts or URLs such as "https://index.docker.io/v1/".
+//
+// Parameters:
+//   - path: The path of the file
+//
+// Returns:
+//   - map[string]registry.AuthConfig: The credentials keyed by registry host, as
+//     expected by Worker.Registries
+//   - error: If the file cannot be read or an entry is invalid
+func LoadRegistries(path string) (map[string]registry.AuthConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry config: %w", err)
+	}
+
+	var cfg registryConfig
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse registry config %s: %w", path, err)
+	}
+
+	registries := make(map[string]registry.AuthConfig, len(cfg.Auths))
+	for key, auth := range cfg.Auths {
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth of registry %s in %s: %w", key, path, err)
+			}
+			user, password, ok := strings.Cut(string(decoded), ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid auth of registry %s in %s: must encode user:password", key, path)
+			}
+			auth.Username, auth.Password, auth.Auth = user, password, ""
+		}
+		if auth.Username == "" && auth.IdentityToken == "" {
+			return nil, fmt.Errorf("registry %s in %s has no credentials", key, path)
+		}
+
+		if auth.ServerAddress == "" {
+			auth.ServerAddress = key
+		}
+		registries[registryHost(key)] = auth
+	}
+	return registries, nil
+}
+
+// registryHost normalizes a key of the auths of a Docker config to the registry
+// host returned by task.RegistryHost, e.g. "https://index.docker.io/v1/" to
+// "docker.io".
+func registryHost(key string) string {
+	host := key
+	if _, rest, ok := strings.Cut(host, "://"); ok {
+		host = rest
+	}
+	host, _, _ = strings.Cut(host, "/")
+	switch host {
+	case "index.docker.io", "registry-1.docker.io":
+		return "docker.io"
+	}
+	return host
+}
@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/metrics"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+// DefaultStatsHistory is the number of usage samples kept per task.
+const DefaultStatsHistory = 60
+
+// usageRing is a fixed-size buffer of the most recent usage samples of a task.
+type usageRing struct {
+	buf  []task.Usage
+	next int
+	full bool
+}
+
+func newUsageRing(size int) *usageRing {
+	return &usageRing{buf: make([]task.Usage, size)}
+}
+
+func (r *usageRing) add(u task.Usage) {
+	r.buf[r.next] = u
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// items returns the samples in the buffer, oldest first.
+func (r *usageRing) items() []task.Usage {
+	if !r.full {
+		return append([]task.Usage(nil), r.buf[:r.next]...)
+	}
+	return append(append([]task.Usage(nil), r.buf[r.next:]...), r.buf[:r.next]...)
+}
+
+// recordUsage appends a usage sample to the history of a task.
+func (w *Worker) recordUsage(id uuid.UUID, u task.Usage) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	r, ok := w.history[id]
+	if !ok {
+		size := w.StatsHistory
+		if size <= 0 {
+			size = DefaultStatsHistory
+		}
+		r = newUsageRing(size)
+		w.history[id] = r
+	}
+	r.add(u)
+}
+
+// forgetUsage drops the samples and usage history of a task.
+func (w *Worker) forgetUsage(id uuid.UUID) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.samples, id)
+	delete(w.history, id)
+}
+
+// GetStats returns the recent usage history of a task.
+//
+// Parameters:
+//   - id: The ID of the task
+//
+// Returns:
+//   - task.Stats: The history and its summary
+//   - bool: false if no usage has been recorded for the task
+func (w *Worker) GetStats(id uuid.UUID) (task.Stats, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	r, ok := w.history[id]
+	if !ok {
+		return task.Stats{}, false
+	}
+	return task.NewStats(id, r.items()), true
+}
+
+// GetAllStats returns the recent usage history of every task with recorded usage.
+func (w *Worker) GetAllStats() []task.Stats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	stats := make([]task.Stats, 0, len(w.history))
+	for id, r := range w.history {
+		stats = append(stats, task.NewStats(id, r.items()))
+	}
+	return stats
+}
+
+// recordTaskStates updates the task metrics from the states of the stored tasks.
+func (w *Worker) recordTaskStates() {
+	tasks, err := w.Db.List()
+	if err != nil {
+		w.logger().Error("Error listing tasks", "error", err)
+		return
+	}
+
+	counts := make(map[task.State]int, len(task.States))
+	for _, t := range tasks {
+		counts[t.State]++
+	}
+	for _, s := range task.States {
+		metrics.WorkerTasks.WithLabelValues(s.String()).Set(float64(counts[s]))
+	}
+}
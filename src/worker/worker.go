@@ -1,56 +1,161 @@
 package worker
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
+	"github.com/utkarsh5026/Orchestra/logging"
 	"github.com/utkarsh5026/Orchestra/store"
+	"github.com/utkarsh5026/Orchestra/tracing"
 	"github.com/utkarsh5026/Orchestra/utils"
 
+	"github.com/docker/docker/api/types/registry"
 	"github.com/golang-collections/collections/queue"
 	"github.com/google/uuid"
 	"github.com/utkarsh5026/Orchestra/task"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/utkarsh5026/Orchestra/worker")
+
 type Worker struct {
 	Name      string
 	Queue     queue.Queue
 	Db        store.Store[uuid.UUID, *task.Task]
+	Docker    *task.DockerClient
 	TaskCount int
+	// Registries holds worker-side credentials keyed by registry host (e.g. "ghcr.io").
+	Registries map[string]registry.AuthConfig
+	// DataDir holds the artifacts collected from jobs and the inputs staged for tasks.
+	DataDir string
+	// Peers are the addresses (host:port) of the other workers input artifacts
+	// may be fetched from. Inputs held by any other address are refused.
+	Peers []string
+	// StatsHistory is the number of usage samples kept per task; zero means DefaultStatsHistory.
+	StatsHistory int
+
+	mu       sync.Mutex
+	starting map[uuid.UUID]context.CancelFunc
+	pullAuth map[uuid.UUID]registry.AuthConfig
+	samples  map[uuid.UUID]task.Sample
+	history  map[uuid.UUID]*usageRing
+	traces   map[uuid.UUID]map[string]string
+	// queuedStarts counts the start events of each task in Queue, and
+	// cancelled records the tasks stopped while one of them was queued.
+	queuedStarts map[uuid.UUID]int
+	cancelled    map[uuid.UUID]bool
 }
 
-func NewWorker(name string, dt store.Type) *Worker {
+// NewWorker creates a Worker with an empty queue, a task store of the given type
+// and a Docker client shared by all of its container operations.
+//
+// Parameters:
+//   - name: The name of the worker
+//   - dt: The type of store to use for tasks
+//
+// Returns:
+//   - *Worker: The initialized worker
+//   - error: If the Docker client cannot be created
+func NewWorker(name string, dt store.Type) (*Worker, error) {
+	dc, err := task.NewDockerClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize worker %s: %w", name, err)
+	}
+
 	w := Worker{
-		Name:  name,
-		Queue: *queue.New(),
+		Name:       name,
+		Queue:      *queue.New(),
+		Docker:     dc,
+		Registries: make(map[string]registry.AuthConfig),
+		DataDir:    filepath.Join(os.TempDir(), "orchestra", name),
+		starting:   make(map[uuid.UUID]context.CancelFunc),
+		pullAuth:   make(map[uuid.UUID]registry.AuthConfig),
+		samples:    make(map[uuid.UUID]task.Sample),
+		history:    make(map[uuid.UUID]*usageRing),
+		traces:     make(map[uuid.UUID]map[string]string),
+
+		queuedStarts: make(map[uuid.UUID]int),
+		cancelled:    make(map[uuid.UUID]bool),
 	}
-	w.Db = store.NewStore[uuid.UUID, *task.Task](dt)
-	return &w
+	w.Db = store.NewCopyStore[uuid.UUID, task.Task](dt)
+	return &w, nil
 }
 
 // StartTask initializes and runs a new task in a Docker container
 // Parameters:
+//   - ctx: Context controlling the lifetime of the Docker operations
 //   - t: The task.Task to be started and executed
 //
+// While the task is starting it can be aborted with CancelTask; a task cancelled
+// this way is finished as Completed rather than Failed.
+//
 // Returns:
 //   - task.DockerResult containing the container ID and any errors that occurred during startup
-func (w *Worker) StartTask(t *task.Task) task.DockerResult {
+func (w *Worker) StartTask(ctx context.Context, t *task.Task) (result task.DockerResult) {
+	ctx, span := tracer.Start(ctx, "StartTask", trace.WithAttributes(
+		attribute.String("task.id", t.ID.String()),
+		attribute.String("task.image", t.Image),
+	))
+	defer func() {
+		tracing.RecordError(span, result.Error)
+		span.End()
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	w.trackStart(t.ID, cancel)
+	defer w.untrackStart(t.ID)
+
 	t.StartTime = time.Now().UTC()
 	config := task.NewConfig(t)
-	d, err := task.NewDocker(*config)
+	auth, err := w.registryAuth(t)
+	if err != nil {
+		w.taskLogger(t.ID).Error("Error resolving registry credentials", "error", err)
+		t.State = task.Failed
+		utils.UpdateStore(w.Db, t.ID, t)
+		return task.DockerResult{Error: err}
+	}
+	config.RegistryAuth = auth
 
+	binds, err := w.stageInputs(ctx, t)
 	if err != nil {
-		log.Printf("Error creating Docker: %v\n", err)
+		w.taskLogger(t.ID).Error("Error staging inputs", "error", err)
 		t.State = task.Failed
 		utils.UpdateStore(w.Db, t.ID, t)
 		return task.DockerResult{Error: err}
 	}
+	config.Binds = binds
+
+	d := task.NewDocker(*config, w.Docker)
+	result = d.Run(ctx)
 
-	result := d.Run()
+	if errors.Is(result.Error, context.Canceled) {
+		w.taskLogger(t.ID).Info("Start of task was cancelled")
+		if result.ContainerId != "" {
+			// The container was created before the start was cancelled; remove
+			// it with a context of its own, since ctx is already done.
+			rmCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+			if timeout := w.Docker.Timeouts.Stop; timeout > 0 {
+				rmCtx, cancel = context.WithTimeout(context.WithoutCancel(ctx), timeout)
+			}
+			if err := d.Remove(rmCtx, result.ContainerId).Error; err != nil {
+				w.taskLogger(t.ID).Warn("Error removing container of cancelled task", logging.ContainerID, result.ContainerId, "error", err)
+			}
+			cancel()
+		}
+		w.finishTask(t)
+		return result
+	}
 
 	if result.Error != nil {
-		log.Printf("Err running task %v: %v\n", t.ID, result.Error)
+		w.taskLogger(t.ID).Error("Error running task", "error", result.Error)
 		t.State = task.Failed
 		utils.UpdateStore(w.Db, t.ID, t)
 		return result
@@ -59,39 +164,43 @@ func (w *Worker) StartTask(t *task.Task) task.DockerResult {
 	t.ContainerID = result.ContainerId
 	t.State = task.Running
 	utils.UpdateStore(w.Db, t.ID, t)
+	w.taskLogger(t.ID).Info("Task started", logging.ContainerID, t.ContainerID)
 	return result
 }
 
 // StopTask stops and removes a running Docker container for a task
 // Parameters:
+//   - ctx: Context controlling the lifetime of the Docker operations
 //   - t: The task whose container should be stopped
 //
 // Returns:
 //   - task.DockerResult containing the container ID and any errors that occurred during shutdown
-func (w *Worker) StopTask(t *task.Task) task.DockerResult {
-	config := task.NewConfig(t)
-	d, err := task.NewDocker(*config)
-	if err != nil {
-		log.Printf("Error creating Docker: %v\n", err)
-		w.finishTask(t)
-		return task.DockerResult{Error: err}
-	}
+func (w *Worker) StopTask(ctx context.Context, t *task.Task) task.DockerResult {
+	ctx, span := tracer.Start(ctx, "StopTask", trace.WithAttributes(attribute.String("task.id", t.ID.String())))
+	defer span.End()
 
-	result := d.Stop(t.ContainerID)
+	config := task.NewConfig(t)
+	d := task.NewDocker(*config, w.Docker)
+	result := d.Stop(ctx, t.ContainerID)
 	if result.Error != nil {
-		log.Printf("Error stopping container %s: %v\n", t.ContainerID, result.Error)
+		w.taskLogger(t.ID).Error("Error stopping container", logging.ContainerID, t.ContainerID, "error", result.Error)
+		tracing.RecordError(span, result.Error)
 	}
+	t.StopOutcome = result.StopOutcome
 
 	w.finishTask(t)
-	log.Printf("Stopped and removed container %v for task %v\n",
-		t.ContainerID, t.ID)
+	w.taskLogger(t.ID).Info("Stopped and removed container", logging.ContainerID, t.ContainerID)
 	return result
 }
 
 // RunTask processes the next task in the worker's queue.
 //
+// Parameters:
+//   - ctx: Context passed on to the Docker operations of the task
+//
 // State transitions:
 //   - Scheduled -> Running: Starts the task's container via StartTask()
+//   - Failed -> Scheduled: Removes the container of the failed attempt and retries via StartTask()
 //   - Running -> Completed: Stops the task's container via StopTask()
 //
 // Returns:
@@ -101,23 +210,25 @@ func (w *Worker) StopTask(t *task.Task) task.DockerResult {
 //   - Queue is empty
 //   - Invalid state transition requested
 //   - Docker operations fail
-func (w *Worker) RunTask() task.DockerResult {
-	t := w.Queue.Dequeue()
-	if t == nil {
-		log.Println("No tasks to run right now")
+func (w *Worker) RunTask(ctx context.Context) task.DockerResult {
+	taskToRun, ok := w.dequeue()
+	if !ok {
+		w.logger().Debug("No tasks to run right now")
 		return task.DockerResult{Error: nil}
 	}
 
-	taskToRun := t.(*task.Task)
-	taskPersisted, err := w.Db.Get(taskToRun.ID)
-	if err != nil {
-		log.Printf("Error getting task %s: %v\n", taskToRun.ID, err)
-		return task.DockerResult{Error: err}
+	ctx = tracing.Extract(ctx, w.takeTraceContext(taskToRun.ID))
+	if w.startCancelled(taskToRun) {
+		return w.dropStart(ctx, taskToRun)
 	}
 
-	if taskPersisted == nil {
+	taskPersisted, err := w.Db.Get(taskToRun.ID)
+	if errors.Is(err, store.ErrNotFound) {
 		taskPersisted = taskToRun
 		utils.UpdateStore(w.Db, taskToRun.ID, taskPersisted)
+	} else if err != nil {
+		w.taskLogger(taskToRun.ID).Error("Error getting task", "error", err)
+		return task.DockerResult{Error: err}
 	}
 
 	var result task.DockerResult
@@ -125,9 +236,14 @@ func (w *Worker) RunTask() task.DockerResult {
 	if taskPersisted.State.CanTransitionTo(taskToRun.State) {
 		switch taskToRun.State {
 		case task.Scheduled:
-			result = w.StartTask(taskToRun)
+			if taskPersisted.State == task.Failed && taskPersisted.ContainerID != "" {
+				if err := w.removeContainer(ctx, taskPersisted); err != nil {
+					w.taskLogger(taskPersisted.ID).Error("Error removing container of failed attempt", logging.ContainerID, taskPersisted.ContainerID, "error", err)
+				}
+			}
+			result = w.StartTask(ctx, taskToRun)
 		case task.Completed:
-			result = w.StopTask(taskToRun)
+			result = w.StopTask(ctx, taskToRun)
 		default:
 			err := fmt.Errorf("invalid state transition: %v -> %v", taskPersisted.State, taskToRun.State)
 			result.Error = err
@@ -157,60 +273,239 @@ func (w *Worker) GetTasks() ([]*task.Task, error) {
 	return tasks, nil
 }
 
-// RunTasks continuously processes tasks from the worker's queue in an infinite loop.
+// RunTasks continuously processes tasks from the worker's queue until ctx is cancelled.
+// Queued tasks are run one after the other; the loop only waits when the queue is empty.
 //
-// This function runs indefinitely and should be started in a separate goroutine.
+// This function should be started in a separate goroutine.
 // It provides the main task processing loop for the worker.
-func (w *Worker) RunTasks() {
+func (w *Worker) RunTasks(ctx context.Context) {
 	for {
-		if w.Queue.Len() > 0 {
-			result := w.RunTask()
+		if w.queueLen() > 0 {
+			result := w.RunTask(ctx)
 			if result.Error != nil {
-				log.Printf("Error running task: %v\n", result.Error)
+				w.logger().Error("Error running task", "error", result.Error)
 			}
-		} else {
-			log.Println("No tasks to process currently.")
+			if ctx.Err() != nil {
+				return
+			}
+			continue
 		}
 
-		log.Println("Sleeping for 10 seconds.")
-		time.Sleep(10 * time.Second)
+		w.logger().Debug("No tasks to process currently")
+		if !sleep(ctx, 10*time.Second) {
+			return
+		}
 	}
 }
 
 // InspectTask inspects a Docker container associated with a task.
 //
 // Parameters:
+//   - ctx: Context controlling the lifetime of the inspect request
 //   - t: The task.Task object containing the container ID to inspect
 //
 // Returns:
 //   - task.DockerInspectResponse containing container inspection details or error
-func (w *Worker) InspectTask(t task.Task) task.DockerInspectResponse {
+func (w *Worker) InspectTask(ctx context.Context, t task.Task) task.DockerInspectResponse {
 	config := task.NewConfig(&t)
-	d, err := task.NewDocker(*config)
-	if err != nil {
-		log.Printf("Error creating Docker: %v\n", err)
-		return task.DockerInspectResponse{Error: err}
+	d := task.NewDocker(*config, w.Docker)
+	return d.Inspect(ctx, t.ContainerID)
+}
+
+// removeContainer removes the exited container of t.
+func (w *Worker) removeContainer(ctx context.Context, t *task.Task) error {
+	d := task.NewDocker(*task.NewConfig(t), w.Docker)
+	return d.Remove(ctx, t.ContainerID).Error
+}
+
+// dropStart finishes a task whose queued start was cancelled without starting
+// it, removing the container of its failed previous attempt if there is one.
+func (w *Worker) dropStart(ctx context.Context, t *task.Task) task.DockerResult {
+	w.taskLogger(t.ID).Info("Dropping start of task stopped while it was queued")
+	if old, err := w.Db.Get(t.ID); err == nil && old.ContainerID != "" {
+		if err := w.removeContainer(ctx, old); err != nil {
+			w.taskLogger(t.ID).Error("Error removing container of failed attempt", logging.ContainerID, old.ContainerID, "error", err)
+		}
+	}
+	t.ContainerID = ""
+	return task.DockerResult{Error: w.finishTask(t)}
+}
+
+// CancelQueuedTask cancels the start of a task that is still waiting in the
+// queue, so that it is finished as Completed when it is dequeued.
+//
+// Parameters:
+//   - id: The ID of the task to cancel
+//
+// Returns:
+//   - bool: true if a start of the task was queued and has been cancelled
+func (w *Worker) CancelQueuedTask(id uuid.UUID) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.queuedStarts[id] == 0 {
+		return false
+	}
+	w.cancelled[id] = true
+	return true
+}
+
+// startCancelled reports whether t, just dequeued, is a start that was
+// cancelled by CancelQueuedTask.
+func (w *Worker) startCancelled(t *task.Task) bool {
+	if t.State != task.Scheduled {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.cancelled[t.ID] {
+		return false
+	}
+	if w.queuedStarts[t.ID] == 0 {
+		delete(w.cancelled, t.ID)
+	}
+	delete(w.pullAuth, t.ID)
+	return true
+}
+
+// CancelTask aborts the start of a task whose image is still being pulled or
+// whose container is still being created.
+//
+// Parameters:
+//   - id: The ID of the task to cancel
+//
+// Returns:
+//   - bool: true if a start was in progress and has been cancelled
+func (w *Worker) CancelTask(id uuid.UUID) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cancel, ok := w.starting[id]
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+func (w *Worker) trackStart(id uuid.UUID, cancel context.CancelFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.starting[id] = cancel
+}
+
+func (w *Worker) untrackStart(id uuid.UUID) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if cancel, ok := w.starting[id]; ok {
+		cancel()
+		delete(w.starting, id)
 	}
-	return d.Inspect(t.ContainerID)
 }
 
 func (w *Worker) AddTask(t *task.Task) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
 	w.Queue.Enqueue(t)
+	if t.State == task.Scheduled {
+		w.queuedStarts[t.ID]++
+	}
+}
+
+// dequeue removes the next task from the queue, reporting false if it is empty.
+func (w *Worker) dequeue() (*task.Task, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.Queue.Len() == 0 {
+		return nil, false
+	}
+	t := w.Queue.Dequeue().(*task.Task)
+	if t.State == task.Scheduled {
+		if w.queuedStarts[t.ID]--; w.queuedStarts[t.ID] <= 0 {
+			delete(w.queuedStarts, t.ID)
+		}
+	}
+	return t, true
+}
+
+func (w *Worker) queueLen() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.Queue.Len()
+}
+
+// SetPullAuth records registry credentials sent by the manager for a task. They
+// take precedence over the worker's own Registries and are discarded once the
+// task has been started.
+//
+// Parameters:
+//   - id: The ID of the task the credentials belong to
+//   - auth: The registry credentials
+func (w *Worker) SetPullAuth(id uuid.UUID, auth registry.AuthConfig) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pullAuth[id] = auth
+}
+
+// SetTraceContext records the trace of the request that queued a task, so that
+// starting or stopping the task continues that trace.
+//
+// Parameters:
+//   - id: The ID of the queued task
+//   - carrier: The trace context as returned by tracing.Inject
+func (w *Worker) SetTraceContext(id uuid.UUID, carrier map[string]string) {
+	if len(carrier) == 0 {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.traces[id] = carrier
+}
+
+func (w *Worker) takeTraceContext(id uuid.UUID) map[string]string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	carrier := w.traces[id]
+	delete(w.traces, id)
+	return carrier
+}
+
+// registryAuth resolves the encoded credentials used to pull the image of t,
+// preferring credentials sent by the manager over the worker's Registries.
+// It returns an empty string if no credentials apply.
+func (w *Worker) registryAuth(t *task.Task) (string, error) {
+	w.mu.Lock()
+	auth, ok := w.pullAuth[t.ID]
+	delete(w.pullAuth, t.ID)
+	w.mu.Unlock()
+
+	if !ok {
+		host, err := task.RegistryHost(t.Image)
+		if err != nil {
+			return "", err
+		}
+		if auth, ok = w.Registries[host]; !ok {
+			return "", nil
+		}
+	}
+	return task.EncodeRegistryAuth(auth)
 }
 
 // UpdateTasks continuously monitors and updates task status at specified intervals.
 //
 // Parameters:
+//   - ctx: Context whose cancellation stops the loop
 //   - d: The duration to wait between status checks
 //
-// This function runs indefinitely and should be started in a separate goroutine.
-func (w *Worker) UpdateTasks(d time.Duration) {
+// This function runs until ctx is cancelled and should be started in a separate goroutine.
+func (w *Worker) UpdateTasks(ctx context.Context, d time.Duration) {
 	for {
-		log.Println("Checking status of tasks")
-		w.updateTasks()
-		log.Println("Task updates completed")
-		log.Printf("Sleeping for %v seconds\n", d)
-		time.Sleep(d)
+		w.logger().Debug("Checking status of tasks")
+		w.updateTasks(ctx)
+		w.recordTaskStates()
+		if !sleep(ctx, d) {
+			return
+		}
 	}
 }
 
@@ -218,28 +513,180 @@ func (w *Worker) UpdateTasks(d time.Duration) {
 //
 // Any errors encountered during listing tasks, inspecting containers, or updating
 // task state are logged but do not stop processing of other tasks.
-func (w *Worker) updateTasks() {
+func (w *Worker) updateTasks(ctx context.Context) {
 	tasks, err := w.Db.List()
 	if err != nil {
-		log.Printf("Error listing tasks: %v\n", err)
+		w.logger().Error("Error listing tasks", "error", err)
 		return
 	}
 
+	now := time.Now().UTC()
 	for _, t := range tasks {
+		if t.Expired(now) {
+			w.cleanupTask(ctx, t)
+			continue
+		}
+
 		if t.State != task.Running {
+			w.mu.Lock()
+			delete(w.samples, t.ID)
+			w.mu.Unlock()
 			continue
 		}
 
-		inspect := w.InspectTask(*t)
+		if t.DeadlineExceeded(now) {
+			w.failDeadlineExceeded(ctx, t)
+			continue
+		}
+
+		inspect := w.InspectTask(ctx, *t)
 		if inspect.Error != nil {
-			log.Printf("Error inspecting container %s: %v\n", t.ContainerID, inspect.Error)
+			w.taskLogger(t.ID).Error("Error inspecting container", logging.ContainerID, t.ContainerID, "error", inspect.Error)
 			continue
 		}
 
 		if inspect.Inspect.State.Status == "exited" {
-			log.Printf("Container %s exited with status %d\n", t.ContainerID, inspect.Inspect.State.ExitCode)
-			t.State = task.Failed
+			exitCode := inspect.Inspect.State.ExitCode
+			w.taskLogger(t.ID).Info("Container exited", logging.ContainerID, t.ContainerID, "exit_code", exitCode)
+			t.State = t.ExitState(exitCode)
+			t.ExitCode = exitCode
+			t.EndTime = time.Now().UTC()
+			if t.State == task.Completed {
+				if err := w.collectOutputs(ctx, t); err != nil {
+					w.taskLogger(t.ID).Error("Error collecting outputs of job", "error", err)
+					t.State = task.Failed
+					t.Reason = task.ReasonOutputsFailed
+				}
+			}
 			utils.UpdateStore(w.Db, t.ID, t)
+			continue
+		}
+
+		if t.HealthCheck != nil {
+			w.checkHealth(ctx, t)
+		}
+		w.sampleUsage(ctx, t)
+	}
+}
+
+// sampleUsage reads the resource usage of a running task and, once two samples
+// are available, records its utilization on the task and in its usage history.
+func (w *Worker) sampleUsage(ctx context.Context, t *task.Task) {
+	d := task.NewDocker(*task.NewConfig(t), w.Docker)
+	cur, err := d.Sample(ctx, t.ContainerID)
+	if err != nil {
+		w.taskLogger(t.ID).Warn("Error sampling usage", "error", err)
+		return
+	}
+
+	w.mu.Lock()
+	prev, ok := w.samples[t.ID]
+	w.samples[t.ID] = cur
+	w.mu.Unlock()
+
+	if ok {
+		usage := task.NewUsage(prev, cur)
+		w.recordUsage(t.ID, usage)
+		t.Usage = &usage
+		utils.UpdateStore(w.Db, t.ID, t)
+	}
+}
+
+// checkHealth runs the health check of a running task and records the result.
+func (w *Worker) checkHealth(ctx context.Context, t *task.Task) {
+	d := task.NewDocker(*task.NewConfig(t), w.Docker)
+	health, err := d.Probe(ctx, t.ContainerID, *t.HealthCheck)
+	if err != nil {
+		w.taskLogger(t.ID).Warn("Health check failed", "error", err)
+	}
+
+	t.Health = health
+	utils.UpdateStore(w.Db, t.ID, t)
+}
+
+// failDeadlineExceeded kills a job that has run past its ActiveDeadline and marks it Failed.
+// The container is kept so that it can be inspected until the job's TTL expires.
+func (w *Worker) failDeadlineExceeded(ctx context.Context, t *task.Task) {
+	w.taskLogger(t.ID).Warn("Job exceeded its active deadline, killing it", "active_deadline", t.ActiveDeadline)
+	if err := w.Docker.API().ContainerKill(ctx, t.ContainerID, "SIGKILL"); err != nil {
+		w.taskLogger(t.ID).Error("Error killing container", logging.ContainerID, t.ContainerID, "error", err)
+		return
+	}
+
+	t.State = task.Failed
+	t.Reason = task.ReasonDeadlineExceeded
+	t.EndTime = time.Now().UTC()
+	utils.UpdateStore(w.Db, t.ID, t)
+}
+
+// cleanupTask removes the container, artifacts and inputs of a finished job whose
+// TTL has expired and forgets the job.
+func (w *Worker) cleanupTask(ctx context.Context, t *task.Task) {
+	if err := w.removeTask(ctx, t); err != nil {
+		w.taskLogger(t.ID).Error("Error cleaning up expired job", "error", err)
+		return
+	}
+	w.taskLogger(t.ID).Info("Cleaned up job after its TTL expired")
+}
+
+// ErrTaskNotFinished is returned by RemoveTask for a task that is still running.
+var ErrTaskNotFinished = errors.New("task is not finished")
+
+// RemoveTask removes the container, artifacts and inputs of a finished task and
+// forgets the task, as is done for jobs whose TTL has expired.
+//
+// Parameters:
+//   - ctx: Context controlling the lifetime of the Docker operations
+//   - id: The ID of the task
+//
+// Returns:
+//   - error: Wrapping store.ErrNotFound if the task is unknown, ErrTaskNotFinished
+//     if it is still running, or the error that stopped the removal
+func (w *Worker) RemoveTask(ctx context.Context, id uuid.UUID) error {
+	t, err := w.Db.Get(id)
+	if err != nil {
+		return fmt.Errorf("failed to get task %s: %w", id, err)
+	}
+	if !t.IsFinished() {
+		return ErrTaskNotFinished
+	}
+	return w.removeTask(ctx, t)
+}
+
+// removeTask removes the container and data of t and forgets it.
+func (w *Worker) removeTask(ctx context.Context, t *task.Task) error {
+	if t.ContainerID != "" {
+		if err := w.removeContainer(ctx, t); err != nil {
+			return fmt.Errorf("failed to remove container %s: %w", t.ContainerID, err)
+		}
+	}
+	if err := w.removeTaskData(t.ID); err != nil {
+		return fmt.Errorf("failed to remove data: %w", err)
+	}
+	if err := w.Db.Delete(t.ID); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	w.forgetUsage(t.ID)
+	return nil
+}
+
+// MonitorRuntime periodically checks that the Docker daemon is reachable and
+// reconnects the worker's shared client when it is not.
+//
+// Parameters:
+//   - ctx: Context whose cancellation stops the loop
+//   - d: The duration to wait between health checks
+//
+// This function runs until ctx is cancelled and should be started in a separate goroutine.
+func (w *Worker) MonitorRuntime(ctx context.Context, d time.Duration) {
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, d)
+		if err := w.Docker.EnsureConnected(checkCtx); err != nil {
+			w.logger().Error("Docker health check failed", "error", err)
+		}
+		cancel()
+		if !sleep(ctx, d) {
+			return
 		}
 	}
 }
@@ -249,3 +696,29 @@ func (w *Worker) finishTask(t *task.Task) error {
 	t.EndTime = time.Now().UTC()
 	return w.Db.Put(t.ID, t)
 }
+
+// sleep waits for d or until ctx is cancelled.
+//
+// Returns:
+//   - bool: false if ctx was cancelled before d elapsed
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// logger returns the default logger with the name of the worker attached.
+func (w *Worker) logger() *slog.Logger {
+	return slog.With(logging.Worker, w.Name)
+}
+
+// taskLogger returns the logger of the worker with the ID of a task attached.
+func (w *Worker) taskLogger(id uuid.UUID) *slog.Logger {
+	return w.logger().With(logging.TaskID, id)
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/utkarsh5026/Orchestra/store"
+	"github.com/utkarsh5026/Orchestra/task"
+)
+
+func TestCancelQueuedTask(t *testing.T) {
+	w, err := NewWorker("test", store.InMemoryStoreType)
+	if err != nil {
+		t.Fatalf("NewWorker() error = %v", err)
+	}
+
+	queued := &task.Task{ID: uuid.New(), Image: "nginx:1.27", State: task.Scheduled}
+	w.AddTask(queued)
+	if w.CancelQueuedTask(uuid.New()) {
+		t.Error("CancelQueuedTask() of a task that is not queued = true, want false")
+	}
+	if !w.CancelQueuedTask(queued.ID) {
+		t.Fatal("CancelQueuedTask() of a queued task = false, want true")
+	}
+
+	if res := w.RunTask(context.Background()); res.Error != nil {
+		t.Fatalf("RunTask() error = %v", res.Error)
+	}
+	got, err := w.Db.Get(queued.ID)
+	if err != nil {
+		t.Fatalf("cancelled task not recorded: %v", err)
+	}
+	if got.State != task.Completed || got.ContainerID != "" {
+		t.Errorf("task is %s with container %q, want Completed without a container", got.State, got.ContainerID)
+	}
+	if len(w.cancelled) != 0 || len(w.queuedStarts) != 0 {
+		t.Errorf("cancelled = %v, queuedStarts = %v, want both empty", w.cancelled, w.queuedStarts)
+	}
+}